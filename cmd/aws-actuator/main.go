@@ -28,34 +28,33 @@ import (
 	"os"
 	"os/exec"
 	"os/user"
-	"time"
+	"path/filepath"
+	"sync"
+	"text/tabwriter"
 
 	flag "github.com/spf13/pflag"
 
 	goflag "flag"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/golang/glog"
 	"github.com/spf13/cobra"
 
-	awsclient "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/aws/client"
 	clusterv1 "sigs.k8s.io/cluster-api/pkg/apis/cluster/v1alpha1"
 
 	"github.com/ghodss/yaml"
 	apiv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
-	kubernetesfake "k8s.io/client-go/kubernetes/fake"
 
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 
 	"text/template"
 
-	"k8s.io/apimachinery/pkg/util/wait"
-
-	"github.com/openshift/cluster-api-actuator-pkg/pkg/e2e/framework"
-	"github.com/openshift/cluster-api-actuator-pkg/pkg/manifests"
 	"sigs.k8s.io/cluster-api-provider-aws/cmd/aws-actuator/utils"
-	awsclientwrapper "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/aws/actuators/machine"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/bootstrapper"
 	testutils "sigs.k8s.io/cluster-api-provider-aws/test/utils"
 )
 
@@ -65,9 +64,6 @@ const (
 	region                   = "us-east-1"
 	size                     = "t1.micro"
 	awsCredentialsSecretName = "aws-credentials-secret"
-
-	pollInterval           = 5 * time.Second
-	timeoutPoolAWSInterval = 10 * time.Minute
 )
 
 func usage() {
@@ -80,73 +76,185 @@ var rootCmd = &cobra.Command{
 }
 
 func createCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "create",
-		Short: "Create machine instance for specified cluster",
+		Short: "Create machine instance(s) for specified cluster",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkFlags(cmd); err != nil {
-				return err
-			}
-			cluster, machine, awsCredentials, userData, err := readClusterResources(
-				&manifestParams{
-					ClusterID: cmd.Flag("environment-id").Value.String(),
-				},
-				cmd.Flag("cluster").Value.String(),
-				cmd.Flag("machine").Value.String(),
-				cmd.Flag("aws-credentials").Value.String(),
-				cmd.Flag("userdata").Value.String(),
-			)
-			if err != nil {
-				return err
-			}
-
-			actuator := utils.CreateActuator(machine, awsCredentials, userData)
-			result, err := actuator.CreateMachine(cluster, machine)
-			if err != nil {
-				return err
-			}
-			fmt.Printf("Machine creation was successful! InstanceID: %s\n", *result.InstanceId)
-			return nil
+			return runBatch(cmd, func(cluster *clusterv1.Cluster, machine *clusterv1.Machine, awsCredentials, userData *apiv1.Secret) (string, string, error) {
+				actuator := utils.CreateActuator(machine, awsCredentials, userData)
+				result, err := actuator.CreateMachine(cluster, machine)
+				if err != nil {
+					return "", "Error", err
+				}
+				return *result.InstanceId, "Created", nil
+			})
 		},
 	}
+	addConcurrencyFlag(cmd)
+	return cmd
 }
 
 func deleteCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "delete",
-		Short: "Delete machine instance",
+		Short: "Delete machine instance(s)",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkFlags(cmd); err != nil {
-				return err
-			}
-			cluster, machine, awsCredentials, userData, err := readClusterResources(
-				&manifestParams{
-					ClusterID: cmd.Flag("environment-id").Value.String(),
-				},
-				cmd.Flag("cluster").Value.String(),
-				cmd.Flag("machine").Value.String(),
-				cmd.Flag("aws-credentials").Value.String(),
-				cmd.Flag("userdata").Value.String(),
-			)
-			if err != nil {
-				return err
-			}
-
-			actuator := utils.CreateActuator(machine, awsCredentials, userData)
-			err = actuator.DeleteMachine(cluster, machine)
-			if err != nil {
-				return err
-			}
-			fmt.Printf("Machine delete operation was successful.\n")
-			return nil
+			return runBatch(cmd, func(cluster *clusterv1.Cluster, machine *clusterv1.Machine, awsCredentials, userData *apiv1.Secret) (string, string, error) {
+				actuator := utils.CreateActuator(machine, awsCredentials, userData)
+				if err := actuator.DeleteMachine(cluster, machine); err != nil {
+					return "", "Error", err
+				}
+				return "", "Deleted", nil
+			})
 		},
 	}
+	addConcurrencyFlag(cmd)
+	return cmd
 }
 
 func existsCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "exists",
-		Short: "Determine if underlying machine instance exists",
+		Short: "Determine if underlying machine instance(s) exist",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBatch(cmd, func(cluster *clusterv1.Cluster, machine *clusterv1.Machine, awsCredentials, userData *apiv1.Secret) (string, string, error) {
+				actuator := utils.CreateActuator(machine, awsCredentials, userData)
+				exists, err := actuator.Exists(context.TODO(), cluster, machine)
+				if err != nil {
+					return "", "Error", err
+				}
+				if exists {
+					return "", "Exists", nil
+				}
+				return "", "NotFound", nil
+			})
+		},
+	}
+	addConcurrencyFlag(cmd)
+	return cmd
+}
+
+func addConcurrencyFlag(cmd *cobra.Command) {
+	cmd.Flags().IntP("concurrency", "", 4, "Number of machine manifests to operate on in parallel")
+}
+
+// batchResult is one machine's outcome from runBatch, printed as a row in the summary table.
+type batchResult struct {
+	Name       string
+	InstanceID string
+	Status     string
+	Error      string
+}
+
+// machineOperation performs a single-machine actuator call and reports the instance ID (if any)
+// and a short human-readable status for the results table.
+type machineOperation func(cluster *clusterv1.Cluster, machine *clusterv1.Machine, awsCredentials, userData *apiv1.Secret) (instanceID, status string, err error)
+
+// runBatch resolves the --machine flag to one or more manifests, runs op against each with a
+// worker pool bounded by --concurrency, and prints an aggregated results table.
+func runBatch(cmd *cobra.Command, op machineOperation) error {
+	if err := checkFlags(cmd); err != nil {
+		return err
+	}
+
+	machinePaths, err := resolveMachineManifests(cmd.Flag("machine").Value.String())
+	if err != nil {
+		return err
+	}
+
+	concurrency, err := cmd.Flags().GetInt("concurrency")
+	if err != nil || concurrency <= 0 {
+		concurrency = 4
+	}
+
+	results := make([]batchResult, len(machinePaths))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, machinePath := range machinePaths {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, machinePath string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = runOneMachine(cmd, machinePath, op)
+		}(i, machinePath)
+	}
+	wg.Wait()
+
+	printBatchResults(results)
+
+	for _, result := range results {
+		if result.Error != "" {
+			return fmt.Errorf("one or more machines failed, see table above")
+		}
+	}
+	return nil
+}
+
+func runOneMachine(cmd *cobra.Command, machinePath string, op machineOperation) batchResult {
+	result := batchResult{Name: machinePath}
+
+	cluster, machine, awsCredentials, userData, err := readClusterResources(
+		&manifestParams{
+			ClusterID: cmd.Flag("environment-id").Value.String(),
+		},
+		cmd.Flag("cluster").Value.String(),
+		machinePath,
+		cmd.Flag("aws-credentials").Value.String(),
+		cmd.Flag("userdata").Value.String(),
+	)
+	if err != nil {
+		result.Status = "Error"
+		result.Error = err.Error()
+		return result
+	}
+	result.Name = machine.Name
+
+	instanceID, status, err := op(cluster, machine, awsCredentials, userData)
+	result.InstanceID = instanceID
+	result.Status = status
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// resolveMachineManifests expands machineLoc into the list of machine manifests to operate on.
+// machineLoc may be a single file, a directory (every *.yaml file in it), or a glob pattern.
+func resolveMachineManifests(machineLoc string) ([]string, error) {
+	info, statErr := os.Stat(machineLoc)
+	if statErr == nil && !info.IsDir() {
+		return []string{machineLoc}, nil
+	}
+
+	pattern := machineLoc
+	if statErr == nil && info.IsDir() {
+		pattern = filepath.Join(machineLoc, "*.yaml")
+	}
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --machine glob %q: %v", machineLoc, err)
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no machine manifests found at %q", machineLoc)
+	}
+	return matches, nil
+}
+
+func printBatchResults(results []batchResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tINSTANCEID\tSTATUS\tERROR")
+	for _, result := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", result.Name, result.InstanceID, result.Status, result.Error)
+	}
+	w.Flush()
+}
+
+func updateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "update",
+		Short: "Update machine instance for specified cluster",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := checkFlags(cmd); err != nil {
 				return err
@@ -165,20 +273,103 @@ func existsCommand() *cobra.Command {
 			}
 
 			actuator := utils.CreateActuator(machine, awsCredentials, userData)
-			exists, err := actuator.Exists(context.TODO(), cluster, machine)
+
+			observed, err := actuator.Describe(cluster, machine)
 			if err != nil {
 				return err
 			}
-			if exists {
-				fmt.Printf("Underlying machine's instance exists.\n")
-			} else {
-				fmt.Printf("Underlying machine's instance not found.\n")
+
+			if err := actuator.Update(cluster, machine); err != nil {
+				return err
+			}
+
+			reconciled, err := actuator.Describe(cluster, machine)
+			if err != nil {
+				return err
 			}
+
+			printInstanceDiff(observed, reconciled)
+			fmt.Printf("Machine update operation was successful.\n")
 			return nil
 		},
 	}
 }
 
+// printInstanceDiff prints the fields of observed (the instance state before the update) that
+// differ from reconciled (the instance state after calling actuator.Update), so an operator can
+// see at a glance what, if anything, was actually changed in AWS.
+func printInstanceDiff(observed, reconciled *ec2.Instance) {
+	changed := false
+
+	if aws.StringValue(observed.InstanceType) != aws.StringValue(reconciled.InstanceType) {
+		changed = true
+		fmt.Printf("InstanceType: %q -> %q\n", aws.StringValue(observed.InstanceType), aws.StringValue(reconciled.InstanceType))
+	}
+
+	if aws.StringValue(observed.SubnetId) != aws.StringValue(reconciled.SubnetId) {
+		changed = true
+		fmt.Printf("SubnetId: %q -> %q\n", aws.StringValue(observed.SubnetId), aws.StringValue(reconciled.SubnetId))
+	}
+
+	observedGroups := securityGroupIDs(observed.SecurityGroups)
+	reconciledGroups := securityGroupIDs(reconciled.SecurityGroups)
+	if !stringSlicesEqual(observedGroups, reconciledGroups) {
+		changed = true
+		fmt.Printf("SecurityGroups: %v -> %v\n", observedGroups, reconciledGroups)
+	}
+
+	observedTags := instanceTags(observed.Tags)
+	reconciledTags := instanceTags(reconciled.Tags)
+	for key, reconciledValue := range reconciledTags {
+		if observedValue, ok := observedTags[key]; !ok || observedValue != reconciledValue {
+			changed = true
+			fmt.Printf("Tags[%v]: %q -> %q\n", key, observedValue, reconciledValue)
+		}
+	}
+	for key, observedValue := range observedTags {
+		if _, ok := reconciledTags[key]; !ok {
+			changed = true
+			fmt.Printf("Tags[%v]: %q -> <removed>\n", key, observedValue)
+		}
+	}
+
+	if !changed {
+		fmt.Printf("No differences between observed and desired instance state.\n")
+	}
+}
+
+func securityGroupIDs(groups []*ec2.GroupIdentifier) []string {
+	ids := make([]string, 0, len(groups))
+	for _, g := range groups {
+		ids = append(ids, aws.StringValue(g.GroupId))
+	}
+	return ids
+}
+
+func instanceTags(tags []*ec2.Tag) map[string]string {
+	m := make(map[string]string, len(tags))
+	for _, t := range tags {
+		m[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+	}
+	return m
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]bool, len(a))
+	for _, v := range a {
+		seen[v] = true
+	}
+	for _, v := range b {
+		if !seen[v] {
+			return false
+		}
+	}
+	return true
+}
+
 func readMachineManifest(manifestParams *manifestParams, manifestLoc string) (*clusterv1.Machine, error) {
 	machine := &clusterv1.Machine{}
 	manifestBytes, err := ioutil.ReadFile(manifestLoc)
@@ -203,28 +394,57 @@ func readMachineManifest(manifestParams *manifestParams, manifestLoc string) (*c
 	return machine, nil
 }
 
-func createSecretAndWait(f *framework.Framework, secret *apiv1.Secret) error {
-	_, err := f.KubeClient.CoreV1().Secrets(secret.Namespace).Create(secret)
-	if err != nil {
-		return err
+// bootstrapFixtures builds the test namespace, cluster, and AWS credentials secret that both
+// `bootstrap` and `bootstrap teardown` construct deterministically from --environment-id.
+func bootstrapFixtures(machinePrefix string) (*apiv1.Namespace, *clusterv1.Cluster, *apiv1.Secret) {
+	testNamespace := &apiv1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+	}
+
+	testCluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      machinePrefix,
+			Namespace: testNamespace.Name,
+		},
+		Spec: clusterv1.ClusterSpec{
+			ClusterNetwork: clusterv1.ClusterNetworkingConfig{
+				Services: clusterv1.NetworkRanges{
+					CIDRBlocks: []string{"10.0.0.1/24"},
+				},
+				Pods: clusterv1.NetworkRanges{
+					CIDRBlocks: []string{"10.0.0.1/24"},
+				},
+				ServiceDomain: "example.com",
+			},
+		},
 	}
 
-	err = wait.Poll(framework.PollInterval, framework.PoolTimeout, func() (bool, error) {
-		_, err := f.KubeClient.CoreV1().Secrets(secret.Namespace).Get(secret.Name, metav1.GetOptions{})
-		return err == nil, nil
-	})
-	return err
+	awsCredentialsSecret := testutils.GenerateAwsCredentialsSecretFromEnv(awsCredentialsSecretName, testNamespace.Name)
+
+	return testNamespace, testCluster, awsCredentialsSecret
+}
+
+// stateFilePathFromFlags resolves --state-file, falling back to defaultStateFilePath when unset.
+func stateFilePathFromFlags(cmd *cobra.Command, machinePrefix string) (string, error) {
+	if path := cmd.Flag("state-file").Value.String(); path != "" {
+		return path, nil
+	}
+	return defaultStateFilePath(machinePrefix)
 }
 
 func bootstrapCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "bootstrap",
-		Short: "Bootstrap kubernetes cluster with kubeadm",
+		Short: "Bootstrap kubernetes cluster using a pluggable, resume-safe bootstrapper",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			machinePrefix := cmd.Flag("environment-id").Value.String()
 
+			kind := bootstrapper.Kind(cmd.Flag("bootstrapper").Value.String())
 			mastermachinepk := cmd.Flag("master-machine-private-key").Value.String()
-			if mastermachinepk == "" {
+			hostedControlPlane, _ := cmd.Flags().GetBool("hosted-control-plane")
+			if kind == bootstrapper.KindKubeadm && mastermachinepk == "" && !hostedControlPlane {
 				return fmt.Errorf("--master-machine-private-key needs to be set")
 			}
 
@@ -235,165 +455,198 @@ func bootstrapCommand() *cobra.Command {
 				return fmt.Errorf("AWS_SECRET_ACCESS_KEY env needs to be set")
 			}
 
-			testNamespace := &apiv1.Namespace{
-				ObjectMeta: metav1.ObjectMeta{
-					Name: "test",
-				},
-			}
-
-			testCluster := &clusterv1.Cluster{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      machinePrefix,
-					Namespace: testNamespace.Name,
-				},
-				Spec: clusterv1.ClusterSpec{
-					ClusterNetwork: clusterv1.ClusterNetworkingConfig{
-						Services: clusterv1.NetworkRanges{
-							CIDRBlocks: []string{"10.0.0.1/24"},
-						},
-						Pods: clusterv1.NetworkRanges{
-							CIDRBlocks: []string{"10.0.0.1/24"},
-						},
-						ServiceDomain: "example.com",
-					},
-				},
+			stateFilePath, err := stateFilePathFromFlags(cmd, machinePrefix)
+			if err != nil {
+				return err
 			}
-
-			awsCredentialsSecret := testutils.GenerateAwsCredentialsSecretFromEnv(awsCredentialsSecretName, testNamespace.Name)
-
-			// Create master machine and verify the master node is ready
-			masterUserDataSecret, err := manifests.MasterMachineUserDataSecret(
-				"masteruserdatasecret",
-				testNamespace.Name,
-				[]string{"\\$(curl -s http://169.254.169.254/latest/meta-data/public-hostname)", "\\$(curl -s http://169.254.169.254/latest/meta-data/public-ipv4)"},
-			)
+			state, err := loadBootstrapState(stateFilePath)
 			if err != nil {
 				return err
 			}
+			if state.WorkerMachineSetCreated {
+				fmt.Printf("Worker MachineSet already recorded in %q; nothing to do. Run `bootstrap teardown` first to start over.\n", stateFilePath)
+				return nil
+			}
 
-			masterMachineProviderConfig, err := testutils.MasterMachineProviderConfig(awsCredentialsSecret.Name, masterUserDataSecret.Name, testCluster.Name)
+			b, err := bootstrapper.GetClusterBootstrapper(kind, bootstrapper.Images{
+				MachineControllerImage:  cmd.Flag("machine-controller-image").Value.String(),
+				MachineManagerImage:     cmd.Flag("machine-manager-image").Value.String(),
+				NodelinkControllerImage: cmd.Flag("nodelink-controller-image").Value.String(),
+			}, mastermachinepk)
 			if err != nil {
 				return err
 			}
 
-			masterMachine := manifests.MasterMachine(testCluster.Name, testCluster.Namespace, masterMachineProviderConfig)
+			testNamespace, testCluster, awsCredentialsSecret := bootstrapFixtures(machinePrefix)
 
-			glog.Infof("Creating master machine")
+			var info *bootstrapper.ControlPlaneInfo
+			var restConfig *rest.Config
 
-			actuator := utils.CreateActuator(masterMachine, awsCredentialsSecret, masterUserDataSecret)
-			result, err := actuator.CreateMachine(testCluster, masterMachine)
-			if err != nil {
-				glog.Error(err)
-				return err
-			}
+			if hostedControlPlane {
+				kubeconfigPath := cmd.Flag("kubeconfig").Value.String()
+				if kubeconfigPath == "" {
+					return fmt.Errorf("--kubeconfig needs to be set when --hosted-control-plane is used")
+				}
 
-			glog.Infof("Master machine created with ipv4: %v, InstanceId: %v", *result.PrivateIpAddress, *result.InstanceId)
+				glog.Infof("Skipping master creation; registering workers against %q", kubeconfigPath)
+				restConfig, err = hostedControlPlaneRestConfig(kubeconfigPath, cmd.Flag("context").Value.String())
+				if err != nil {
+					return err
+				}
+				info = &bootstrapper.ControlPlaneInfo{}
+			} else if state.MasterInstanceID != "" {
+				glog.Infof("Reusing master machine %v recorded in %q", state.MasterInstanceID, stateFilePath)
+				info = &bootstrapper.ControlPlaneInfo{
+					MasterInstanceID: state.MasterInstanceID,
+					MasterPrivateIP:  state.MasterPrivateIP,
+				}
 
-			masterMachinePrivateIP := ""
-			err = wait.Poll(pollInterval, timeoutPoolAWSInterval, func() (bool, error) {
-				glog.Info("Waiting for master machine PublicDNS")
-				result, err := actuator.Describe(testCluster, masterMachine)
+				restConfig, err = b.FetchKubeconfig(testCluster, testNamespace, awsCredentialsSecret, info)
 				if err != nil {
-					glog.Info(err)
-					return false, nil
+					glog.Errorf("Unable to pull kubeconfig: %v", err)
+					return err
+				}
+			} else {
+				info, err = b.PrepareControlPlane(testCluster, testNamespace, awsCredentialsSecret)
+				if err != nil {
+					glog.Error(err)
+					return err
 				}
 
-				glog.Infof("PublicDnsName: %v\n", *result.PublicDnsName)
-				if *result.PublicDnsName == "" {
-					return false, nil
+				state.MasterInstanceID = info.MasterInstanceID
+				state.MasterPrivateIP = info.MasterPrivateIP
+				if err := saveBootstrapState(stateFilePath, state); err != nil {
+					return err
 				}
 
-				masterMachinePrivateIP = *result.PrivateIpAddress
-				return true, nil
-			})
-			if err != nil {
-				glog.Errorf("Unable to get DNS name: %v", err)
-				return err
+				restConfig, err = b.FetchKubeconfig(testCluster, testNamespace, awsCredentialsSecret, info)
+				if err != nil {
+					glog.Errorf("Unable to pull kubeconfig: %v", err)
+					return err
+				}
 			}
 
-			f := framework.Framework{
-				SSH: &framework.SSHConfig{
-					Key:  mastermachinepk,
-					User: "ec2-user",
-				},
+			if restConfig == nil {
+				fmt.Printf("Control plane prepared; no kubeconfig available, skipping worker join.\n")
+				return nil
 			}
 
-			objList := []runtime.Object{awsCredentialsSecret}
-			fakeKubeClient := kubernetesfake.NewSimpleClientset(objList...)
-			awsClient, err := awsclient.NewClient(fakeKubeClient, awsCredentialsSecret.Name, awsCredentialsSecret.Namespace, region)
-			if err != nil {
-				glog.Errorf("Unable to create aws client: %v", err)
-				return err
+			if !state.StackDeployed {
+				if err := b.DeployStack(restConfig, info, testCluster, testNamespace, awsCredentialsSecret); err != nil {
+					return err
+				}
+
+				state.StackDeployed = true
+				if err := saveBootstrapState(stateFilePath, state); err != nil {
+					return err
+				}
+			} else {
+				glog.Infof("Cluster-api stack already recorded in %q; skipping redeploy", stateFilePath)
 			}
 
-			acw := awsclientwrapper.NewAwsClientWrapper(awsClient)
-			glog.Infof("Collecting master kubeconfig")
-			restConfig, err := f.GetMasterMachineRestConfig(masterMachine, acw)
-			if err != nil {
-				glog.Errorf("Unable to pull kubeconfig: %v", err)
+			if err := b.CreateWorkerMachineSet(restConfig, info, testCluster, testNamespace, awsCredentialsSecret); err != nil {
 				return err
 			}
 
-			clusterFramework, err := framework.NewFrameworkFromConfig(
-				restConfig,
-				&framework.SSHConfig{
-					Key:  mastermachinepk,
-					User: "ec2-user",
-				},
-			)
-			if err != nil {
+			state.WorkerMachineSetCreated = true
+			if err := saveBootstrapState(stateFilePath, state); err != nil {
 				return err
 			}
 
-			clusterFramework.ErrNotExpected = func(err error) {
-				if err != nil {
-					glog.Fatal(err)
-				}
-			}
+			return nil
+		},
+	}
 
-			clusterFramework.By = func(msg string) {
-				glog.Info(msg)
-			}
+	cmd.PersistentFlags().StringP("manifests", "", "", "Directory with bootstrapping manifests")
+	cmd.PersistentFlags().StringP("master-machine-private-key", "", "", "Private key file of the master machine to pull kubeconfig")
+	cmd.PersistentFlags().StringP("bootstrapper", "", string(bootstrapper.KindKubeadm), "Bootstrapper to use: kubeadm, cloud-init, or eks")
+	cmd.PersistentFlags().StringP("machine-controller-image", "", "openshift/origin-aws-machine-controllers:v4.0.0", "Image for the machine controller")
+	cmd.PersistentFlags().StringP("machine-manager-image", "", "openshift/origin-aws-machine-controllers:v4.0.0", "Image for the machine manager")
+	cmd.PersistentFlags().StringP("nodelink-controller-image", "", "registry.svc.ci.openshift.org/openshift/origin-v4.0-2019-01-03-031244@sha256:152c0a4ea7cda1731e45af87e33909421dcde7a8fcf4e973cd098a8bae892c50", "Image for the nodelink controller")
+	cmd.PersistentFlags().Bool("hosted-control-plane", false, "Skip master creation and register workers against an existing cluster instead")
+	cmd.PersistentFlags().StringP("kubeconfig", "", "", "Kubeconfig of the existing cluster to register workers against (requires --hosted-control-plane)")
+	cmd.PersistentFlags().StringP("context", "", "", "Context within --kubeconfig to use (requires --hosted-control-plane)")
+	cmd.PersistentFlags().StringP("state-file", "", "", "Path to the bootstrap state file, default ~/.aws-actuator/<environment-id>.json")
+
+	cmd.AddCommand(bootstrapTeardownCommand())
+	return cmd
+}
 
-			clusterFramework.MachineControllerImage = "openshift/origin-aws-machine-controllers:v4.0.0"
-			clusterFramework.MachineManagerImage = "openshift/origin-aws-machine-controllers:v4.0.0"
-			clusterFramework.NodelinkControllerImage = "registry.svc.ci.openshift.org/openshift/origin-v4.0-2019-01-03-031244@sha256:152c0a4ea7cda1731e45af87e33909421dcde7a8fcf4e973cd098a8bae892c50"
+func bootstrapTeardownCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "teardown",
+		Short: "Delete whatever `bootstrap` created, using its state file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			machinePrefix := cmd.Flag("environment-id").Value.String()
+			kind := bootstrapper.Kind(cmd.Flag("bootstrapper").Value.String())
 
-			glog.Info("Waiting for all nodes to come up")
-			err = clusterFramework.WaitForNodesToGetReady(1)
+			stateFilePath, err := stateFilePathFromFlags(cmd, machinePrefix)
 			if err != nil {
 				return err
 			}
-
-			glog.Infof("Creating %q namespace", testNamespace.Name)
-			if _, err := clusterFramework.KubeClient.CoreV1().Namespaces().Create(testNamespace); err != nil {
+			state, err := loadBootstrapState(stateFilePath)
+			if err != nil {
 				return err
 			}
 
-			clusterFramework.DeployClusterAPIStack(testNamespace.Name, "")
-			clusterFramework.CreateClusterAndWait(testCluster)
-			createSecretAndWait(clusterFramework, awsCredentialsSecret)
-
-			workerUserDataSecret, err := manifests.WorkerMachineUserDataSecret("workeruserdatasecret", testNamespace.Name, masterMachinePrivateIP)
+			b, err := bootstrapper.GetClusterBootstrapper(kind, bootstrapper.Images{
+				MachineControllerImage:  cmd.Flag("machine-controller-image").Value.String(),
+				MachineManagerImage:     cmd.Flag("machine-manager-image").Value.String(),
+				NodelinkControllerImage: cmd.Flag("nodelink-controller-image").Value.String(),
+			}, cmd.Flag("master-machine-private-key").Value.String())
 			if err != nil {
 				return err
 			}
 
-			createSecretAndWait(clusterFramework, workerUserDataSecret)
-			workerMachineSetProviderConfig, err := testutils.WorkerMachineSetProviderConfig(awsCredentialsSecret.Name, workerUserDataSecret.Name, testCluster.Name)
-			if err != nil {
+			testNamespace, testCluster, awsCredentialsSecret := bootstrapFixtures(machinePrefix)
+
+			info := &bootstrapper.ControlPlaneInfo{
+				MasterInstanceID: state.MasterInstanceID,
+				MasterPrivateIP:  state.MasterPrivateIP,
+			}
+
+			var restConfig *rest.Config
+			if state.WorkerMachineSetCreated {
+				restConfig, err = b.FetchKubeconfig(testCluster, testNamespace, awsCredentialsSecret, info)
+				if err != nil {
+					glog.Errorf("Unable to fetch kubeconfig to delete the worker MachineSet; it will not be torn down: %v", err)
+					restConfig = nil
+				}
+			}
+
+			if err := b.Teardown(restConfig, info, testCluster, testNamespace, awsCredentialsSecret); err != nil {
 				return err
 			}
-			workerMachineSet := manifests.WorkerMachineSet(testCluster.Name, testCluster.Namespace, workerMachineSetProviderConfig)
-			clusterFramework.CreateMachineSetAndWait(workerMachineSet, acw)
 
+			if err := os.Remove(stateFilePath); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("unable to remove state file %q: %v", stateFilePath, err)
+			}
+
+			fmt.Printf("Teardown complete.\n")
 			return nil
 		},
 	}
+}
 
-	cmd.PersistentFlags().StringP("manifests", "", "", "Directory with bootstrapping manifests")
-	cmd.PersistentFlags().StringP("master-machine-private-key", "", "", "Private key file of the master machine to pull kubeconfig")
-	return cmd
+// hostedControlPlaneRestConfig loads a rest.Config for an existing management cluster so that
+// bootstrap --hosted-control-plane can register workers against it without provisioning a master.
+func hostedControlPlaneRestConfig(kubeconfigPath, context string) (*rest.Config, error) {
+	rawConfig, err := clientcmd.LoadFromFile(kubeconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load kubeconfig %q: %v", kubeconfigPath, err)
+	}
+
+	overrides := &clientcmd.ConfigOverrides{}
+	if context != "" {
+		overrides.CurrentContext = context
+	}
+
+	restConfig, err := clientcmd.NewDefaultClientConfig(*rawConfig, overrides).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("unable to build client config from %q: %v", kubeconfigPath, err)
+	}
+
+	return restConfig, nil
 }
 
 func cmdRun(binaryPath string, args ...string) ([]byte, error) {
@@ -422,6 +675,8 @@ func init() {
 
 	rootCmd.AddCommand(existsCommand())
 
+	rootCmd.AddCommand(updateCommand())
+
 	rootCmd.AddCommand(bootstrapCommand())
 
 	flag.CommandLine.AddGoFlagSet(goflag.CommandLine)
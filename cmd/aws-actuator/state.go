@@ -0,0 +1,80 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+)
+
+// bootstrapState is what makes `bootstrap` resume-safe: it records the steps already completed
+// for a given --environment-id so that a re-invocation after a failure does not recreate the
+// master EC2 instance or redo work the previous run already finished.
+type bootstrapState struct {
+	MasterInstanceID        string `json:"masterInstanceId,omitempty"`
+	MasterPrivateIP         string `json:"masterPrivateIp,omitempty"`
+	StackDeployed           bool   `json:"stackDeployed"`
+	WorkerMachineSetCreated bool   `json:"workerMachineSetCreated"`
+}
+
+// defaultStateFilePath returns ~/.aws-actuator/<environmentID>.json, the default --state-file.
+func defaultStateFilePath(environmentID string) (string, error) {
+	u, err := user.Current()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine home directory for default --state-file: %v", err)
+	}
+	return filepath.Join(u.HomeDir, ".aws-actuator", environmentID+".json"), nil
+}
+
+// loadBootstrapState reads the state file at path, returning a zero-value state if it does not
+// exist yet (the common case for a first run).
+func loadBootstrapState(path string) (*bootstrapState, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &bootstrapState{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read state file %q: %v", path, err)
+	}
+
+	state := &bootstrapState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("unable to parse state file %q: %v", path, err)
+	}
+	return state, nil
+}
+
+// saveBootstrapState persists state to path, creating its parent directory if necessary.
+func saveBootstrapState(path string, state *bootstrapState) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("unable to create state file directory for %q: %v", path, err)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("unable to write state file %q: %v", path, err)
+	}
+	return nil
+}
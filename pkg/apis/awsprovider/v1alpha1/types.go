@@ -0,0 +1,345 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// AWSMachineProviderConfig is the Schema for the awsmachineproviderconfigs API
+// It is used by the AWS machine actuator to create a single machine instance.
+type AWSMachineProviderConfig struct {
+	// AMI is the reference to the AMI from which to create the machine instance.
+	AMI AWSResourceReference `json:"ami"`
+
+	// InstanceType is the type of instance to create, e.g. m4.xlarge.
+	InstanceType string `json:"instanceType"`
+
+	// IAMInstanceProfile is a reference to an IAM role to assign to the instance.
+	IAMInstanceProfile *AWSResourceReference `json:"iamInstanceProfile,omitempty"`
+
+	// Tags is the set of additional tags to add to the instance.
+	Tags []TagSpecification `json:"tags,omitempty"`
+
+	// KeyName is the name of the SSH key to attach to the instance.
+	KeyName string `json:"keyName,omitempty"`
+
+	// DeviceIndex is the index of the device on the instance for the network interface attachment.
+	DeviceIndex int64 `json:"deviceIndex"`
+
+	// PublicIP specifies whether the instance should get a public IP.
+	PublicIP *bool `json:"publicIp,omitempty"`
+
+	// SecurityGroups is an array of references to security groups that should be applied to the instance.
+	SecurityGroups []AWSResourceReference `json:"securityGroups,omitempty"`
+
+	// Subnet is a reference to the subnet to use for this instance.
+	Subnet *AWSResourceReference `json:"subnet,omitempty"`
+
+	// Placement specifies where to create the instance in AWS.
+	Placement Placement `json:"placement,omitempty"`
+
+	// UserDataSecret references a secret that should be used as the instance's user data.
+	UserDataSecret *corev1.LocalObjectReference `json:"userDataSecret,omitempty"`
+
+	// CredentialsSecret references a secret holding the AWS credentials used to provision the instance.
+	CredentialsSecret *corev1.LocalObjectReference `json:"credentialsSecret,omitempty"`
+
+	// SpotMarketOptions allows the instance to be created as an EC2 Spot Instance/Spot Fleet
+	// request instead of an On-Demand instance. Leave nil to use On-Demand.
+	SpotMarketOptions *SpotMarketOptions `json:"spotMarketOptions,omitempty"`
+
+	// RootVolume configures the instance's root EBS volume. If omitted, the AMI's default
+	// root volume is used unchanged.
+	RootVolume *Volume `json:"rootVolume,omitempty"`
+
+	// DataVolumes is a list of additional EBS volumes to attach to the instance at launch.
+	DataVolumes []Volume `json:"dataVolumes,omitempty"`
+
+	// MetadataOptions configures the instance metadata service, in particular IMDSv2.
+	MetadataOptions *MetadataOptions `json:"metadataOptions,omitempty"`
+
+	// CPUOptions allows disabling hyperthreading or reducing the visible core count, for
+	// HPC and licensing-sensitive workloads.
+	CPUOptions *CPUOptions `json:"cpuOptions,omitempty"`
+}
+
+// CPUOptions specifies the number of CPU cores and threads per core for an instance. Only
+// certain instance types support overriding these values; see the EC2 documentation for the
+// valid combinations for a given instance type.
+type CPUOptions struct {
+	// CoreCount is the number of CPU cores to expose to the instance.
+	CoreCount int64 `json:"coreCount"`
+
+	// ThreadsPerCore is the number of threads per core. Set to 1 to disable hyperthreading.
+	ThreadsPerCore int64 `json:"threadsPerCore"`
+}
+
+// Volume describes an EBS volume to attach to an instance, either as the root device or as an
+// additional data volume.
+type Volume struct {
+	// DeviceName is the device name exposed to the instance, e.g. "/dev/sdb". Required for
+	// data volumes; optional for the root volume, whose device name is looked up from the AMI
+	// when this is left empty.
+	DeviceName string `json:"deviceName,omitempty"`
+
+	// Size is the size of the volume in GiB.
+	Size int64 `json:"size"`
+
+	// Type is the EBS volume type, e.g. "gp2", "gp3", "io1". Defaults to the AMI/AWS default
+	// when omitted.
+	Type string `json:"type,omitempty"`
+
+	// IOPS is the number of IOPS to provision. Only valid for io1/io2/gp3 volumes.
+	IOPS int64 `json:"iops,omitempty"`
+
+	// Throughput is the throughput to provision, in MiB/s. Only valid for gp3 volumes.
+	Throughput int64 `json:"throughput,omitempty"`
+
+	// Encrypted specifies whether the volume should be encrypted.
+	Encrypted *bool `json:"encrypted,omitempty"`
+
+	// KMSKeyID is the ARN or ID of the KMS key used to encrypt the volume. Only used when
+	// Encrypted is true; when omitted the account default EBS key is used.
+	KMSKeyID string `json:"kmsKeyId,omitempty"`
+
+	// DeleteOnTermination specifies whether the volume is deleted when the instance is
+	// terminated. Defaults to true when omitted.
+	DeleteOnTermination *bool `json:"deleteOnTermination,omitempty"`
+}
+
+// MetadataOptions configures the Instance Metadata Service (IMDS) for an instance.
+type MetadataOptions struct {
+	// HTTPTokens determines whether IMDSv2 is required. Set to "required" to force IMDSv2
+	// and disable the insecure IMDSv1 fallback; "optional" allows both.
+	// +kubebuilder:validation:Enum=optional;required
+	HTTPTokens string `json:"httpTokens,omitempty"`
+
+	// HTTPPutResponseHopLimit caps the number of network hops the metadata token can travel,
+	// which limits exposure of the token to containers running on the instance.
+	HTTPPutResponseHopLimit int64 `json:"httpPutResponseHopLimit,omitempty"`
+
+	// HTTPEndpoint enables or disables the instance metadata endpoint entirely.
+	// +kubebuilder:validation:Enum=enabled;disabled
+	HTTPEndpoint string `json:"httpEndpoint,omitempty"`
+}
+
+// SpotMarketOptions defines the options available to a user when configuring
+// Machines to run as Spot instances.
+type SpotMarketOptions struct {
+	// MaxPrice is the maximum price the user is willing to pay for the Spot instance,
+	// expressed as a decimal string (e.g. "0.1783"). If omitted, the maximum price
+	// defaults to the On-Demand price of the instance type at the time of request.
+	MaxPrice *string `json:"maxPrice,omitempty"`
+
+	// SpotInstanceType is the Spot Instance request type, either "one-time" or "persistent".
+	// A persistent request is re-submitted after the instance is interrupted or stopped.
+	// +optional
+	// +kubebuilder:validation:Enum=one-time;persistent
+	SpotInstanceType string `json:"spotInstanceType,omitempty"`
+
+	// BlockDurationMinutes reserves the Spot instance for the given duration and is
+	// one of 60, 120, 180, 240, 300, or 360. Leave unset for no block duration.
+	BlockDurationMinutes *int64 `json:"blockDurationMinutes,omitempty"`
+
+	// InstanceInterruptionBehavior is the behavior when a Spot Instance is interrupted,
+	// one of "hibernate", "stop", or "terminate". Defaults to "terminate".
+	InstanceInterruptionBehavior string `json:"instanceInterruptionBehavior,omitempty"`
+}
+
+// AWSMachineProviderStatus is the Schema for the awsmachineproviderconfigs API
+// It is used to hold the status of the AWS machine instance for more details about the status, events and error.
+type AWSMachineProviderStatus struct {
+	// InstanceID is the instance ID of the machine created in AWS.
+	InstanceID *string `json:"instanceId,omitempty"`
+
+	// InstanceState is the state of the AWS instance for this machine.
+	InstanceState *string `json:"instanceState,omitempty"`
+
+	// SpotRequestID is the ID of the EC2 Spot Instance request backing this machine, if any.
+	// It is taken from the instance CreateOrGetMachine returns and cleared when that instance
+	// carries no Spot request ID; it is kept after the request is fulfilled, whatever the
+	// request type.
+	SpotRequestID *string `json:"spotRequestId,omitempty"`
+}
+
+// AWSResourceReference is a reference to a specific AWS resource by ID, ARN, or filters.
+// Only one of ID, ARN or Filters may be specified. Specifying more than one will result in
+// a validation error.
+type AWSResourceReference struct {
+	// ID of resource.
+	ID *string `json:"id,omitempty"`
+
+	// ARN of resource.
+	ARN *string `json:"arn,omitempty"`
+
+	// Filters is a set of key/value pairs used to identify a resource. For details on the rules
+	// for filter names and values, see the EC2 API documentation.
+	Filters []Filter `json:"filters,omitempty"`
+}
+
+// Filter is a filter used to identify an AWS resource.
+type Filter struct {
+	// Name of the filter. Filter names are case-sensitive.
+	Name string `json:"name"`
+
+	// Values includes one or more filter values. Filter values are case-sensitive.
+	Values []string `json:"values"`
+}
+
+// TagSpecification is the name/value pair for a tag.
+type TagSpecification struct {
+	// Name of the tag.
+	Name string `json:"name"`
+
+	// Value of the tag.
+	Value string `json:"value"`
+}
+
+// Placement indicates where to create the instance in AWS.
+type Placement struct {
+	// Region is the region to use to create the instance.
+	Region string `json:"region,omitempty"`
+
+	// AvailabilityZone is the availability zone of the instance.
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+
+	// GroupName is the name of an existing, or to-be-created, placement group that the
+	// instance should be launched into.
+	GroupName string `json:"groupName,omitempty"`
+
+	// GroupStrategy is the strategy used when GroupName doesn't already exist and must be
+	// created. One of "cluster", "spread", "partition". Defaults to "cluster". Ignored if
+	// GroupName already exists, since AWS placement groups cannot change strategy after
+	// creation.
+	// +kubebuilder:validation:Enum=cluster;spread;partition
+	GroupStrategy string `json:"groupStrategy,omitempty"`
+
+	// Tenancy indicates whether the instance runs on shared, dedicated, or a specific
+	// dedicated host. One of "default", "dedicated", "host". Defaults to "default".
+	// +kubebuilder:validation:Enum=default;dedicated;host
+	Tenancy string `json:"tenancy,omitempty"`
+
+	// HostID is the ID of the dedicated host to launch the instance onto. Only used when
+	// Tenancy is "host".
+	HostID string `json:"hostId,omitempty"`
+
+	// PartitionNumber is the partition of a "partition" strategy placement group to launch
+	// the instance into. Only valid when GroupStrategy is "partition"; AWS rejects it for
+	// any other strategy.
+	PartitionNumber int64 `json:"partitionNumber,omitempty"`
+}
+
+// SecurityGroupRole is the role of a managed security group.
+type SecurityGroupRole string
+
+const (
+	// SecurityGroupBastion is the security group for bastion nodes.
+	SecurityGroupBastion = SecurityGroupRole("bastion")
+
+	// SecurityGroupAPIServerLB is the security group for the API Server Load Balancer.
+	SecurityGroupAPIServerLB = SecurityGroupRole("apiserver-lb")
+
+	// SecurityGroupLB is the security group for the legacy load balancer.
+	SecurityGroupLB = SecurityGroupRole("lb")
+
+	// SecurityGroupControlPlane is the security group for control plane nodes.
+	SecurityGroupControlPlane = SecurityGroupRole("controlplane")
+
+	// SecurityGroupNode is the security group for worker nodes.
+	SecurityGroupNode = SecurityGroupRole("node")
+)
+
+// NetworkInterface encapsulates the configuration of a network interface to attach to an instance.
+type NetworkInterface struct {
+	// DeviceIndex is the index of the device on the instance for the network interface attachment.
+	DeviceIndex *int64 `json:"deviceIndex,omitempty"`
+
+	// AssociatePublicIpAddress specifies whether to assign a public IP address to the network interface.
+	AssociatePublicIpAddress *bool `json:"associatePublicIpAddress,omitempty"`
+
+	// SubnetId is the ID of the subnet to associate with the network interface.
+	SubnetId *string `json:"subnetId,omitempty"`
+
+	// Groups is the list of security group IDs to associate with the network interface.
+	Groups []*string `json:"groups,omitempty"`
+}
+
+// Instance describes an AWS instance.
+type Instance struct {
+	// ID is the instance ID.
+	ID string `json:"id,omitempty"`
+
+	// Type is the type of instance, e.g. m4.xlarge.
+	Type string `json:"type,omitempty"`
+
+	// ImageID is the ID of the AMI used to create the instance.
+	ImageID string `json:"imageId,omitempty"`
+
+	// IAMProfile is the name or ARN of the IAM instance profile attached to the instance.
+	IAMProfile string `json:"iamProfile,omitempty"`
+
+	// SubnetID is the ID of the subnet the instance is in.
+	SubnetID string `json:"subnetId,omitempty"`
+
+	// SecurityGroupIDs are the IDs of the security groups attached to the instance.
+	SecurityGroupIDs []string `json:"securityGroupIds,omitempty"`
+
+	// NetworkInterfaces is the list of network interfaces attached to the instance.
+	NetworkInterfaces []*NetworkInterface `json:"networkInterfaces,omitempty"`
+
+	// KeyName is the name of the SSH key attached to the instance.
+	KeyName *string `json:"keyName,omitempty"`
+
+	// UserData is the base64 encoded user data provided to the instance.
+	UserData *string `json:"userData,omitempty"`
+
+	// EBSOptimized specifies whether the instance is optimized for EBS I/O.
+	EBSOptimized *bool `json:"ebsOptimized,omitempty"`
+
+	// Tags is the set of tags applied to the instance.
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// State is the current state of the instance.
+	State string `json:"state,omitempty"`
+
+	// SpotMarketOptions, when set, requests the instance be launched as an EC2 Spot Instance.
+	SpotMarketOptions *SpotMarketOptions `json:"spotMarketOptions,omitempty"`
+
+	// SpotRequestID is the Spot Instance request that backs this instance, if any.
+	SpotRequestID string `json:"spotRequestId,omitempty"`
+
+	// ClientToken is the idempotency token supplied to RunInstances so that a retry after a
+	// transient error does not launch a second instance for the same Machine.
+	ClientToken string `json:"clientToken,omitempty"`
+
+	// RootVolume configures the instance's root EBS volume.
+	RootVolume *Volume `json:"rootVolume,omitempty"`
+
+	// DataVolumes is a list of additional EBS volumes to attach to the instance.
+	DataVolumes []Volume `json:"dataVolumes,omitempty"`
+
+	// MetadataOptions configures the instance metadata service.
+	MetadataOptions *MetadataOptions `json:"metadataOptions,omitempty"`
+
+	// Placement carries the placement group, tenancy, and dedicated host settings through to
+	// RunInstances.
+	Placement *Placement `json:"placement,omitempty"`
+
+	// CPUOptions carries the core count/threads-per-core settings through to RunInstances.
+	CPUOptions *CPUOptions `json:"cpuOptions,omitempty"`
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bootstrapper abstracts the "stand up a management cluster's control
+// plane and join workers to it" flow behind a small interface so that the
+// aws-actuator `bootstrap` command is not tied to a single, kubeadm-specific
+// implementation.
+package bootstrapper
+
+import (
+	"github.com/pkg/errors"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+
+	clusterv1 "sigs.k8s.io/cluster-api/pkg/apis/cluster/v1alpha1"
+)
+
+// Images holds the controller images a Bootstrapper deploys onto the
+// control plane it prepares. They are supplied by the caller (CLI flags)
+// rather than hardcoded so that CI can pin specific builds.
+type Images struct {
+	MachineControllerImage  string
+	MachineManagerImage     string
+	NodelinkControllerImage string
+}
+
+// ControlPlaneInfo is what PrepareControlPlane learns about the control
+// plane it prepared and that the other Bootstrapper methods need in order
+// to finish the job.
+type ControlPlaneInfo struct {
+	// MasterPrivateIP is the private IP address of the master machine, used to
+	// template the worker user data. Empty for bootstrappers that do not
+	// provision a master machine (e.g. eks).
+	MasterPrivateIP string
+
+	// MasterInstanceID is the EC2 instance ID of the master machine, if one was
+	// created.
+	MasterInstanceID string
+}
+
+// Bootstrapper stands up a Cluster API management cluster's control plane and
+// joins worker machines to it. Concrete implementations decide whether (and
+// how) a master EC2 instance is created and how a kubeconfig for the
+// resulting cluster is obtained.
+type Bootstrapper interface {
+	// PrepareControlPlane makes the control plane reachable, provisioning a
+	// master machine first if the implementation requires one.
+	PrepareControlPlane(cluster *clusterv1.Cluster, namespace *apiv1.Namespace, awsCredentialsSecret *apiv1.Secret) (*ControlPlaneInfo, error)
+
+	// FetchKubeconfig returns a rest.Config for the control plane that
+	// PrepareControlPlane made ready. cluster, namespace, and awsCredentialsSecret are the same
+	// values passed to PrepareControlPlane; implementations that reconstruct their master machine
+	// deterministically (rather than relying on in-memory state set by PrepareControlPlane) need
+	// them to be resumable across processes, the same way Teardown already is.
+	FetchKubeconfig(cluster *clusterv1.Cluster, namespace *apiv1.Namespace, awsCredentialsSecret *apiv1.Secret, info *ControlPlaneInfo) (*rest.Config, error)
+
+	// DeployStack deploys the cluster-api stack (namespace, controllers, Cluster object, and
+	// supporting secrets) against the cluster described by restConfig. It is split out from
+	// CreateWorkerMachineSet so that a caller can checkpoint between the two: a failure creating
+	// the worker MachineSet does not require redeploying the stack on retry.
+	DeployStack(restConfig *rest.Config, info *ControlPlaneInfo, cluster *clusterv1.Cluster, namespace *apiv1.Namespace, awsCredentialsSecret *apiv1.Secret) error
+
+	// CreateWorkerMachineSet creates the worker MachineSet and waits for it to come up against
+	// the cluster described by restConfig. It assumes DeployStack already succeeded against the
+	// same restConfig.
+	CreateWorkerMachineSet(restConfig *rest.Config, info *ControlPlaneInfo, cluster *clusterv1.Cluster, namespace *apiv1.Namespace, awsCredentialsSecret *apiv1.Secret) error
+
+	// Teardown deletes whatever bootstrap created: the worker MachineSet (if restConfig is
+	// non-nil, meaning CreateWorkerMachineSet got far enough to create one) and the master machine (if
+	// PrepareControlPlane created one). It must be safe to call with info describing a control
+	// plane that was only partially prepared, and with restConfig nil when no MachineSet was
+	// ever created or its kubeconfig could no longer be fetched.
+	Teardown(restConfig *rest.Config, info *ControlPlaneInfo, cluster *clusterv1.Cluster, namespace *apiv1.Namespace, awsCredentialsSecret *apiv1.Secret) error
+}
+
+// Kind identifies which Bootstrapper implementation to use.
+type Kind string
+
+const (
+	// KindKubeadm provisions a master EC2 instance and bootstraps it with
+	// kubeadm, the original aws-actuator bootstrap behavior.
+	KindKubeadm Kind = "kubeadm"
+	// KindCloudInit provisions a master EC2 instance but only renders its
+	// user data; it does not SSH in to fetch a kubeconfig.
+	KindCloudInit Kind = "cloud-init"
+	// KindEKS never provisions a master EC2 instance; it joins machines to an
+	// existing Amazon EKS cluster.
+	KindEKS Kind = "eks"
+)
+
+// GetClusterBootstrapper returns the Bootstrapper implementation named by
+// kind, analogous to minikube's GetClusterBootstrapper factory.
+// masterMachinePrivateKey is only used by the kubeadm bootstrapper's
+// FetchKubeconfig/DeployStack/CreateWorkerMachineSet steps; it may be left empty for callers (e.g.
+// `bootstrap teardown`) that never reach those steps.
+func GetClusterBootstrapper(kind Kind, images Images, masterMachinePrivateKey string) (Bootstrapper, error) {
+	switch kind {
+	case KindKubeadm:
+		return &KubeadmBootstrapper{Images: images, SSHKey: masterMachinePrivateKey}, nil
+	case KindCloudInit:
+		return &CloudInitBootstrapper{Images: images}, nil
+	case KindEKS:
+		return &EKSBootstrapper{Images: images}, nil
+	default:
+		return nil, errors.Errorf("unknown bootstrapper %q", kind)
+	}
+}
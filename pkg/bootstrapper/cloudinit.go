@@ -0,0 +1,124 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/manifests"
+	clusterv1 "sigs.k8s.io/cluster-api/pkg/apis/cluster/v1alpha1"
+
+	"sigs.k8s.io/cluster-api-provider-aws/cmd/aws-actuator/utils"
+	testutils "sigs.k8s.io/cluster-api-provider-aws/test/utils"
+)
+
+// CloudInitBootstrapper creates a master EC2 instance and renders its user
+// data, but never SSHes into it. It is meant for images that bootstrap
+// themselves entirely from cloud-init and publish their own kubeconfig
+// out-of-band (e.g. to an object store), so there is nothing for this
+// bootstrapper to fetch.
+type CloudInitBootstrapper struct {
+	Images Images
+}
+
+// PrepareControlPlane creates the master machine and renders its user data.
+// It does not wait for the instance to become reachable over SSH, since
+// FetchKubeconfig never needs one.
+func (b *CloudInitBootstrapper) PrepareControlPlane(cluster *clusterv1.Cluster, namespace *apiv1.Namespace, awsCredentialsSecret *apiv1.Secret) (*ControlPlaneInfo, error) {
+	masterUserDataSecret, err := manifests.MasterMachineUserDataSecret(
+		"masteruserdatasecret",
+		namespace.Name,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	masterMachineProviderConfig, err := testutils.MasterMachineProviderConfig(awsCredentialsSecret.Name, masterUserDataSecret.Name, cluster.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	masterMachine := manifests.MasterMachine(cluster.Name, cluster.Namespace, masterMachineProviderConfig)
+
+	glog.Infof("Creating master machine")
+
+	actuator := utils.CreateActuator(masterMachine, awsCredentialsSecret, masterUserDataSecret)
+	result, err := actuator.CreateMachine(cluster, masterMachine)
+	if err != nil {
+		return nil, err
+	}
+
+	glog.Infof("Master machine created with ipv4: %v, InstanceId: %v", *result.PrivateIpAddress, *result.InstanceId)
+
+	return &ControlPlaneInfo{
+		MasterInstanceID: *result.InstanceId,
+		MasterPrivateIP:  *result.PrivateIpAddress,
+	}, nil
+}
+
+// FetchKubeconfig always returns a nil config: the cloud-init bootstrapper
+// deliberately skips the SSH kubeconfig fetch and expects the master to
+// publish its own kubeconfig out-of-band. Callers should treat a nil config
+// as "nothing more to do" rather than an error.
+func (b *CloudInitBootstrapper) FetchKubeconfig(cluster *clusterv1.Cluster, namespace *apiv1.Namespace, awsCredentialsSecret *apiv1.Secret, info *ControlPlaneInfo) (*rest.Config, error) {
+	glog.Infof("cloud-init bootstrapper does not fetch a kubeconfig over SSH; skipping")
+	return nil, nil
+}
+
+// DeployStack always returns an error: this bootstrapper never produces a
+// kubeconfig on its own, and it does not deploy the stack or join workers even
+// when one is supplied (e.g. with --hosted-control-plane).
+func (b *CloudInitBootstrapper) DeployStack(restConfig *rest.Config, info *ControlPlaneInfo, cluster *clusterv1.Cluster, namespace *apiv1.Namespace, awsCredentialsSecret *apiv1.Secret) error {
+	if restConfig == nil {
+		return errors.New("cloud-init bootstrapper requires a kubeconfig supplied out-of-band before workers can join")
+	}
+	return errors.New("cloud-init bootstrapper does not implement worker joining; join workers against the externally supplied kubeconfig directly")
+}
+
+// CreateWorkerMachineSet is never reached: DeployStack always errors, since this bootstrapper
+// never produces a kubeconfig of its own.
+func (b *CloudInitBootstrapper) CreateWorkerMachineSet(restConfig *rest.Config, info *ControlPlaneInfo, cluster *clusterv1.Cluster, namespace *apiv1.Namespace, awsCredentialsSecret *apiv1.Secret) error {
+	return errors.New("cloud-init bootstrapper does not implement worker joining; join workers against the externally supplied kubeconfig directly")
+}
+
+// Teardown deletes the worker MachineSet (if restConfig is non-nil) and the master machine
+// created by PrepareControlPlane.
+func (b *CloudInitBootstrapper) Teardown(restConfig *rest.Config, info *ControlPlaneInfo, cluster *clusterv1.Cluster, namespace *apiv1.Namespace, awsCredentialsSecret *apiv1.Secret) error {
+	if restConfig != nil {
+		if err := teardownWorkerMachineSet(restConfig, cluster, namespace, awsCredentialsSecret); err != nil {
+			return errors.Wrap(err, "failed to delete worker MachineSet")
+		}
+	}
+
+	if info == nil || info.MasterInstanceID == "" {
+		glog.Info("No master machine recorded, nothing to tear down")
+		return nil
+	}
+
+	masterMachine, masterUserDataSecret, err := buildMasterMachine(cluster, namespace, awsCredentialsSecret)
+	if err != nil {
+		return err
+	}
+
+	glog.Infof("Deleting master machine %v", info.MasterInstanceID)
+	actuator := utils.CreateActuator(masterMachine, awsCredentialsSecret, masterUserDataSecret)
+	return actuator.DeleteMachine(cluster, masterMachine)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+	apiv1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/e2e/framework"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/manifests"
+	clusterv1 "sigs.k8s.io/cluster-api/pkg/apis/cluster/v1alpha1"
+
+	testutils "sigs.k8s.io/cluster-api-provider-aws/test/utils"
+)
+
+// createSecretAndWait creates secret against f's cluster and waits for it to
+// be readable back, so that callers can rely on it existing by the time this
+// returns.
+func createSecretAndWait(f *framework.Framework, secret *apiv1.Secret) error {
+	_, err := f.KubeClient.CoreV1().Secrets(secret.Namespace).Create(secret)
+	if err != nil {
+		return err
+	}
+
+	return wait.Poll(framework.PollInterval, framework.PoolTimeout, func() (bool, error) {
+		_, err := f.KubeClient.CoreV1().Secrets(secret.Namespace).Get(secret.Name, metav1.GetOptions{})
+		return err == nil, nil
+	})
+}
+
+const (
+	retrySteps       = 6
+	retryInitialWait = 2 * time.Second
+	retryFactor      = 2.0
+)
+
+// retry calls fn up to retrySteps times in all, with exponentially increasing backoff between
+// attempts, and returns the last error if every attempt fails. It wraps the SSH kubeconfig fetch
+// from a master instance that may not be fully up yet (DNS propagation, sshd starting,
+// kubelet serving), so a transient failure there does not force the whole bootstrap to restart.
+func retry(fn func() error) error {
+	backoff := retryInitialWait
+	var err error
+	for attempt := 0; attempt < retrySteps; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		if attempt == retrySteps-1 {
+			break
+		}
+		time.Sleep(backoff)
+		backoff = time.Duration(float64(backoff) * retryFactor)
+	}
+	return err
+}
+
+// newClusterFramework builds a framework.Framework against restConfig, wiring it to report
+// through glog and use images the same way every Bootstrapper's DeployStack/CreateWorkerMachineSet
+// steps do. sshConfig is nil for bootstrappers (cloud-init, eks) that never SSH into a master.
+func newClusterFramework(restConfig *rest.Config, sshConfig *framework.SSHConfig, images Images) (*framework.Framework, error) {
+	clusterFramework, err := framework.NewFrameworkFromConfig(restConfig, sshConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterFramework.ErrNotExpected = func(err error) {
+		if err != nil {
+			glog.Fatal(err)
+		}
+	}
+	clusterFramework.By = func(msg string) {
+		glog.Info(msg)
+	}
+	clusterFramework.MachineControllerImage = images.MachineControllerImage
+	clusterFramework.MachineManagerImage = images.MachineManagerImage
+	clusterFramework.NodelinkControllerImage = images.NodelinkControllerImage
+
+	return clusterFramework, nil
+}
+
+// ensureNamespace creates namespace, tolerating it already existing, so that DeployStack can be
+// retried after a partial failure (e.g. the stack deployed but CreateWorkerMachineSet did not)
+// without erroring on the namespace already being there.
+func ensureNamespace(f *framework.Framework, namespace *apiv1.Namespace) error {
+	_, err := f.KubeClient.CoreV1().Namespaces().Create(namespace)
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// teardownWorkerMachineSet deletes the worker MachineSet that CreateWorkerMachineSet created
+// against restConfig, the same way it was built there, so that `bootstrap teardown` removes the
+// workers along with the master instead of leaving them running.
+func teardownWorkerMachineSet(restConfig *rest.Config, cluster *clusterv1.Cluster, namespace *apiv1.Namespace, awsCredentialsSecret *apiv1.Secret) error {
+	clusterFramework, err := framework.NewFrameworkFromConfig(restConfig, nil)
+	if err != nil {
+		return err
+	}
+
+	workerMachineSetProviderConfig, err := testutils.WorkerMachineSetProviderConfig(awsCredentialsSecret.Name, "workeruserdatasecret", cluster.Name)
+	if err != nil {
+		return err
+	}
+	workerMachineSet := manifests.WorkerMachineSet(cluster.Name, cluster.Namespace, workerMachineSetProviderConfig)
+
+	glog.Infof("Deleting worker MachineSet %q", workerMachineSet.Name)
+	return clusterFramework.CAPIClient.ClusterV1alpha1().MachineSets(namespace.Name).Delete(workerMachineSet.Name, &metav1.DeleteOptions{})
+}
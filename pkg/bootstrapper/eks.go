@@ -0,0 +1,166 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"encoding/base64"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/e2e/framework"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/manifests"
+	clusterv1 "sigs.k8s.io/cluster-api/pkg/apis/cluster/v1alpha1"
+
+	awsclientwrapper "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/aws/actuators/machine"
+	awsclient "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/aws/client"
+	testutils "sigs.k8s.io/cluster-api-provider-aws/test/utils"
+)
+
+// EKSBootstrapper joins machines to an existing Amazon EKS cluster. Unlike
+// the kubeadm and cloud-init bootstrappers it never provisions a master EC2
+// instance: EKS already runs and manages the control plane, so
+// PrepareControlPlane only needs to look the cluster up and DeployStack/CreateWorkerMachineSet
+// only need to set up the stack and create the worker MachineSet.
+type EKSBootstrapper struct {
+	Images Images
+
+	eksClient   *eks.EKS
+	clusterName string
+}
+
+// PrepareControlPlane looks up the named EKS cluster and confirms it is
+// active. cluster.Name is taken to be the EKS cluster name.
+func (b *EKSBootstrapper) PrepareControlPlane(cluster *clusterv1.Cluster, namespace *apiv1.Namespace, awsCredentialsSecret *apiv1.Secret) (*ControlPlaneInfo, error) {
+	b.eksClient = eks.New(session.Must(session.NewSession()))
+	b.clusterName = cluster.Name
+
+	out, err := b.eksClient.DescribeCluster(&eks.DescribeClusterInput{
+		Name: aws.String(cluster.Name),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to describe EKS cluster %q", cluster.Name)
+	}
+
+	if status := aws.StringValue(out.Cluster.Status); status != eks.ClusterStatusActive {
+		return nil, errors.Errorf("EKS cluster %q is not active yet (status %q)", cluster.Name, status)
+	}
+
+	glog.Infof("Joining workers to existing EKS cluster %q", cluster.Name)
+	return &ControlPlaneInfo{}, nil
+}
+
+// FetchKubeconfig builds a rest.Config from the EKS cluster's endpoint and
+// certificate authority only. It attaches no credentials: the returned config
+// has no bearer token and no exec plugin set. It talks to EKS directly from cluster.Name
+// rather than relying on b.eksClient/b.clusterName, so it also works against a cluster described
+// by an earlier process (e.g. a resumed `bootstrap` run).
+func (b *EKSBootstrapper) FetchKubeconfig(cluster *clusterv1.Cluster, namespace *apiv1.Namespace, awsCredentialsSecret *apiv1.Secret, info *ControlPlaneInfo) (*rest.Config, error) {
+	eksClient := eks.New(session.Must(session.NewSession()))
+	out, err := eksClient.DescribeCluster(&eks.DescribeClusterInput{
+		Name: aws.String(cluster.Name),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to describe EKS cluster")
+	}
+
+	ca, err := base64.StdEncoding.DecodeString(aws.StringValue(out.Cluster.CertificateAuthority.Data))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to decode EKS cluster CA")
+	}
+
+	return &rest.Config{
+		Host: aws.StringValue(out.Cluster.Endpoint),
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData: ca,
+		},
+	}, nil
+}
+
+// DeployStack deploys the cluster-api stack against the existing EKS control plane (via
+// restConfig). There is no master to SSH into, so unlike KubeadmBootstrapper this never builds a
+// framework.SSHConfig or waits on SSH-reachable nodes. It can be retried: creating the namespace
+// tolerates it already existing.
+func (b *EKSBootstrapper) DeployStack(restConfig *rest.Config, info *ControlPlaneInfo, cluster *clusterv1.Cluster, namespace *apiv1.Namespace, awsCredentialsSecret *apiv1.Secret) error {
+	clusterFramework, err := newClusterFramework(restConfig, nil, b.Images)
+	if err != nil {
+		return err
+	}
+
+	glog.Infof("Creating %q namespace", namespace.Name)
+	if err := ensureNamespace(clusterFramework, namespace); err != nil {
+		return err
+	}
+
+	clusterFramework.DeployClusterAPIStack(namespace.Name, "")
+	clusterFramework.CreateClusterAndWait(cluster)
+	return createSecretAndWait(clusterFramework, awsCredentialsSecret)
+}
+
+// CreateWorkerMachineSet creates the worker user data and MachineSet against the existing EKS
+// control plane. It assumes DeployStack already succeeded against the same restConfig.
+func (b *EKSBootstrapper) CreateWorkerMachineSet(restConfig *rest.Config, info *ControlPlaneInfo, cluster *clusterv1.Cluster, namespace *apiv1.Namespace, awsCredentialsSecret *apiv1.Secret) error {
+	clusterFramework, err := newClusterFramework(restConfig, nil, b.Images)
+	if err != nil {
+		return err
+	}
+
+	workerUserDataSecret, err := manifests.WorkerMachineUserDataSecret("workeruserdatasecret", namespace.Name, "")
+	if err != nil {
+		return err
+	}
+
+	if err := createSecretAndWait(clusterFramework, workerUserDataSecret); err != nil {
+		return err
+	}
+
+	workerMachineSetProviderConfig, err := testutils.WorkerMachineSetProviderConfig(awsCredentialsSecret.Name, workerUserDataSecret.Name, cluster.Name)
+	if err != nil {
+		return err
+	}
+
+	objList := []runtime.Object{awsCredentialsSecret}
+	fakeKubeClient := fake.NewSimpleClientset(objList...)
+	awsClient, err := awsclient.NewClient(fakeKubeClient, awsCredentialsSecret.Name, awsCredentialsSecret.Namespace, region)
+	if err != nil {
+		return errors.Wrap(err, "unable to create aws client")
+	}
+	acw := awsclientwrapper.NewAwsClientWrapper(awsClient)
+
+	workerMachineSet := manifests.WorkerMachineSet(cluster.Name, cluster.Namespace, workerMachineSetProviderConfig)
+	glog.Infof("Creating worker MachineSet %q against EKS cluster %q", workerMachineSet.Name, cluster.Name)
+	clusterFramework.CreateMachineSetAndWait(workerMachineSet, acw)
+
+	return nil
+}
+
+// Teardown deletes the worker MachineSet (if restConfig is non-nil). The eks bootstrapper never
+// creates a master machine, so there is nothing else of its own to delete.
+func (b *EKSBootstrapper) Teardown(restConfig *rest.Config, info *ControlPlaneInfo, cluster *clusterv1.Cluster, namespace *apiv1.Namespace, awsCredentialsSecret *apiv1.Secret) error {
+	if restConfig == nil {
+		glog.Info("No worker MachineSet kubeconfig available, nothing to tear down")
+		return nil
+	}
+	return errors.Wrap(teardownWorkerMachineSet(restConfig, cluster, namespace, awsCredentialsSecret), "failed to delete worker MachineSet")
+}
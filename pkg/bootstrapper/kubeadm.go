@@ -0,0 +1,248 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/e2e/framework"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/manifests"
+	clusterv1 "sigs.k8s.io/cluster-api/pkg/apis/cluster/v1alpha1"
+
+	"sigs.k8s.io/cluster-api-provider-aws/cmd/aws-actuator/utils"
+	awsclientwrapper "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/aws/actuators/machine"
+	awsclient "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/aws/client"
+	testutils "sigs.k8s.io/cluster-api-provider-aws/test/utils"
+)
+
+const (
+	pollInterval           = 5 * time.Second
+	timeoutPoolAWSInterval = 10 * time.Minute
+	region                 = "us-east-1"
+)
+
+// KubeadmBootstrapper provisions a master EC2 instance, waits for it to come
+// up, SSHes in to deploy the cluster-api stack with kubeadm, and pulls a
+// kubeconfig back over that same SSH connection. This is the original
+// aws-actuator `bootstrap` behavior.
+type KubeadmBootstrapper struct {
+	Images Images
+	SSHKey string
+}
+
+// buildMasterMachine deterministically reconstructs the master Machine and its user data secret
+// from cluster and awsCredentialsSecret alone, so that a later process (e.g. `bootstrap
+// teardown`) can address the same instance without having to persist the full manifest.
+func buildMasterMachine(cluster *clusterv1.Cluster, namespace *apiv1.Namespace, awsCredentialsSecret *apiv1.Secret) (*clusterv1.Machine, *apiv1.Secret, error) {
+	masterUserDataSecret, err := manifests.MasterMachineUserDataSecret(
+		"masteruserdatasecret",
+		namespace.Name,
+		[]string{"\\$(curl -s http://169.254.169.254/latest/meta-data/public-hostname)", "\\$(curl -s http://169.254.169.254/latest/meta-data/public-ipv4)"},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	masterMachineProviderConfig, err := testutils.MasterMachineProviderConfig(awsCredentialsSecret.Name, masterUserDataSecret.Name, cluster.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return manifests.MasterMachine(cluster.Name, cluster.Namespace, masterMachineProviderConfig), masterUserDataSecret, nil
+}
+
+// PrepareControlPlane creates the master machine and waits for its PublicDNS
+// to be populated so that it can be reached over SSH.
+func (b *KubeadmBootstrapper) PrepareControlPlane(cluster *clusterv1.Cluster, namespace *apiv1.Namespace, awsCredentialsSecret *apiv1.Secret) (*ControlPlaneInfo, error) {
+	masterMachine, masterUserDataSecret, err := buildMasterMachine(cluster, namespace, awsCredentialsSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	glog.Infof("Creating master machine")
+
+	actuator := utils.CreateActuator(masterMachine, awsCredentialsSecret, masterUserDataSecret)
+	result, err := actuator.CreateMachine(cluster, masterMachine)
+	if err != nil {
+		return nil, err
+	}
+
+	glog.Infof("Master machine created with ipv4: %v, InstanceId: %v", *result.PrivateIpAddress, *result.InstanceId)
+
+	info := &ControlPlaneInfo{MasterInstanceID: *result.InstanceId}
+	err = wait.Poll(pollInterval, timeoutPoolAWSInterval, func() (bool, error) {
+		glog.Info("Waiting for master machine PublicDNS")
+		result, err := actuator.Describe(cluster, masterMachine)
+		if err != nil {
+			glog.Info(err)
+			return false, nil
+		}
+
+		glog.Infof("PublicDnsName: %v\n", *result.PublicDnsName)
+		if *result.PublicDnsName == "" {
+			return false, nil
+		}
+
+		info.MasterPrivateIP = *result.PrivateIpAddress
+		return true, nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get DNS name")
+	}
+
+	return info, nil
+}
+
+// FetchKubeconfig SSHes into the master machine and pulls its kubeconfig. It rebuilds the same
+// Machine object PrepareControlPlane built, and its own SSH-configured framework.Framework, rather
+// than relying on in-memory state, the same way Teardown does, so that it also works against a master created by
+// an earlier process (e.g. a resumed `bootstrap` run).
+func (b *KubeadmBootstrapper) FetchKubeconfig(cluster *clusterv1.Cluster, namespace *apiv1.Namespace, awsCredentialsSecret *apiv1.Secret, info *ControlPlaneInfo) (*rest.Config, error) {
+	masterMachine, _, err := buildMasterMachine(cluster, namespace, awsCredentialsSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterFramework := &framework.Framework{
+		SSH: b.kubeadmSSHConfig(),
+	}
+
+	objList := []runtime.Object{}
+	fakeKubeClient := fake.NewSimpleClientset(objList...)
+	awsClient, err := awsclient.NewClient(fakeKubeClient, "", "", region)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create aws client")
+	}
+
+	acw := awsclientwrapper.NewAwsClientWrapper(awsClient)
+	glog.Infof("Collecting master kubeconfig")
+
+	var restConfig *rest.Config
+	err = retry(func() error {
+		var sshErr error
+		restConfig, sshErr = clusterFramework.GetMasterMachineRestConfig(masterMachine, acw)
+		return sshErr
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to pull kubeconfig")
+	}
+
+	return restConfig, nil
+}
+
+// kubeadmSSHConfig returns the SSH config used to reach the master machine this bootstrapper
+// created.
+func (b *KubeadmBootstrapper) kubeadmSSHConfig() *framework.SSHConfig {
+	return &framework.SSHConfig{
+		Key:  b.SSHKey,
+		User: "ec2-user",
+	}
+}
+
+// DeployStack waits for the master node to come up, then deploys the cluster-api stack and the
+// Cluster object and aws-credentials secret it needs, against the cluster described by
+// restConfig. It can be retried: creating namespace tolerates it already existing.
+func (b *KubeadmBootstrapper) DeployStack(restConfig *rest.Config, info *ControlPlaneInfo, cluster *clusterv1.Cluster, namespace *apiv1.Namespace, awsCredentialsSecret *apiv1.Secret) error {
+	clusterFramework, err := newClusterFramework(restConfig, b.kubeadmSSHConfig(), b.Images)
+	if err != nil {
+		return err
+	}
+
+	glog.Info("Waiting for all nodes to come up")
+	if err := clusterFramework.WaitForNodesToGetReady(1); err != nil {
+		return err
+	}
+
+	glog.Infof("Creating %q namespace", namespace.Name)
+	if err := ensureNamespace(clusterFramework, namespace); err != nil {
+		return err
+	}
+
+	clusterFramework.DeployClusterAPIStack(namespace.Name, "")
+	clusterFramework.CreateClusterAndWait(cluster)
+	return createSecretAndWait(clusterFramework, awsCredentialsSecret)
+}
+
+// CreateWorkerMachineSet creates the worker user data and MachineSet against the cluster
+// described by restConfig. It assumes DeployStack already succeeded against the same restConfig.
+func (b *KubeadmBootstrapper) CreateWorkerMachineSet(restConfig *rest.Config, info *ControlPlaneInfo, cluster *clusterv1.Cluster, namespace *apiv1.Namespace, awsCredentialsSecret *apiv1.Secret) error {
+	clusterFramework, err := newClusterFramework(restConfig, b.kubeadmSSHConfig(), b.Images)
+	if err != nil {
+		return err
+	}
+
+	workerUserDataSecret, err := manifests.WorkerMachineUserDataSecret("workeruserdatasecret", namespace.Name, info.MasterPrivateIP)
+	if err != nil {
+		return err
+	}
+
+	if err := createSecretAndWait(clusterFramework, workerUserDataSecret); err != nil {
+		return err
+	}
+
+	workerMachineSetProviderConfig, err := testutils.WorkerMachineSetProviderConfig(awsCredentialsSecret.Name, workerUserDataSecret.Name, cluster.Name)
+	if err != nil {
+		return err
+	}
+
+	objList := []runtime.Object{awsCredentialsSecret}
+	fakeKubeClient := fake.NewSimpleClientset(objList...)
+	awsClient, err := awsclient.NewClient(fakeKubeClient, awsCredentialsSecret.Name, awsCredentialsSecret.Namespace, region)
+	if err != nil {
+		return errors.Wrap(err, "unable to create aws client")
+	}
+	acw := awsclientwrapper.NewAwsClientWrapper(awsClient)
+
+	workerMachineSet := manifests.WorkerMachineSet(cluster.Name, cluster.Namespace, workerMachineSetProviderConfig)
+	clusterFramework.CreateMachineSetAndWait(workerMachineSet, acw)
+
+	return nil
+}
+
+// Teardown deletes the worker MachineSet (if restConfig is non-nil) and the master machine
+// created by PrepareControlPlane. It rebuilds the same Machine object PrepareControlPlane built
+// rather than relying on in-memory state, so that it also works when invoked from
+// `bootstrap teardown` in a fresh process.
+func (b *KubeadmBootstrapper) Teardown(restConfig *rest.Config, info *ControlPlaneInfo, cluster *clusterv1.Cluster, namespace *apiv1.Namespace, awsCredentialsSecret *apiv1.Secret) error {
+	if restConfig != nil {
+		if err := teardownWorkerMachineSet(restConfig, cluster, namespace, awsCredentialsSecret); err != nil {
+			return errors.Wrap(err, "failed to delete worker MachineSet")
+		}
+	}
+
+	if info == nil || info.MasterInstanceID == "" {
+		glog.Info("No master machine recorded, nothing to tear down")
+		return nil
+	}
+
+	masterMachine, masterUserDataSecret, err := buildMasterMachine(cluster, namespace, awsCredentialsSecret)
+	if err != nil {
+		return err
+	}
+
+	glog.Infof("Deleting master machine %v", info.MasterInstanceID)
+	actuator := utils.CreateActuator(masterMachine, awsCredentialsSecret, masterUserDataSecret)
+	return actuator.DeleteMachine(cluster, masterMachine)
+}
@@ -17,7 +17,11 @@ limitations under the License.
 package ec2
 
 import (
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -55,16 +59,219 @@ func (s *Service) InstanceByTags(machine *actuators.MachineScope) (*v1alpha1.Ins
 		return nil, errors.Wrap(err, "failed to describe instances by tags")
 	}
 
-	// TODO: currently just returns the first matched instance, need to
-	// better rationalize how to find the right instance to return if multiple
-	// match
+	var matches []*ec2.Instance
 	for _, res := range out.Reservations {
-		for _, inst := range res.Instances {
-			return converters.SDKToInstance(inst), nil
+		matches = append(matches, res.Instances...)
+	}
+
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	if len(matches) > 1 {
+		ids := make([]string, 0, len(matches))
+		for _, inst := range matches {
+			ids = append(ids, aws.StringValue(inst.InstanceId))
 		}
+		record.Eventf(machine.Machine, "AmbiguousInstance", "Found %d non-terminated instances tagged for machine %q: %v", len(matches), machine.Name(), ids)
+		return nil, errors.Errorf("found %d non-terminated instances with Name tag %q, expected at most 1: %v", len(matches), machine.Name(), ids)
 	}
 
-	return nil, nil
+	return converters.SDKToInstance(matches[0]), nil
+}
+
+// ensurePlacementGroup creates the named placement group with the given strategy, tagged as
+// cluster-owned, if it does not already exist. An empty strategy defaults to "cluster", AWS's
+// own default. Placement groups are lifecycle-managed the same way subnets/SGs are today:
+// created on demand and cleaned up by DeletePlacementGroupIfUnused once nothing references them
+// any more.
+func (s *Service) ensurePlacementGroup(name string, strategy string) error {
+	_, err := s.scope.EC2.DescribePlacementGroups(&ec2.DescribePlacementGroupsInput{
+		GroupNames: aws.StringSlice([]string{name}),
+	})
+	if err == nil {
+		return nil
+	}
+	if !awserrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to describe placement group %q", name)
+	}
+
+	if strategy == "" {
+		strategy = ec2.PlacementStrategyCluster
+	}
+
+	klog.V(2).Infof("Creating placement group %q with strategy %q", name, strategy)
+
+	groupTags := tags.Build(tags.BuildParams{
+		ClusterName: s.scope.Scope.Name(),
+		Lifecycle:   tags.ResourceLifecycleOwned,
+		Name:        aws.String(name),
+	})
+
+	var ec2Tags []*ec2.Tag
+	for key, value := range groupTags {
+		ec2Tags = append(ec2Tags, &ec2.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+
+	input := &ec2.CreatePlacementGroupInput{
+		GroupName: aws.String(name),
+		Strategy:  aws.String(strategy),
+		TagSpecifications: []*ec2.TagSpecification{
+			{ResourceType: aws.String("placement-group"), Tags: ec2Tags},
+		},
+	}
+
+	if _, err := s.scope.EC2.CreatePlacementGroup(input); err != nil {
+		return errors.Wrapf(err, "failed to create placement group %q", name)
+	}
+
+	return nil
+}
+
+// DeletePlacementGroupIfUnused deletes the named placement group once no pending, running,
+// stopping or stopped instance still references it; instances that are shutting down are not
+// counted. Callers should invoke this after terminating a Machine whose
+// MachineConfig.Placement.GroupName was set, so the group is reclaimed after the last owning
+// Machine is torn down.
+func (s *Service) DeletePlacementGroupIfUnused(groupName string) error {
+	if groupName == "" {
+		return nil
+	}
+
+	out, err := s.scope.EC2.DescribeInstances(&ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{Name: aws.String("placement-group-name"), Values: aws.StringSlice([]string{groupName})},
+			filter.EC2.InstanceStates(ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning, ec2.InstanceStateNameStopping, ec2.InstanceStateNameStopped),
+		},
+	})
+	switch {
+	case awserrors.IsNotFound(err):
+		out = &ec2.DescribeInstancesOutput{}
+	case err != nil:
+		return errors.Wrapf(err, "failed to check for instances still using placement group %q", groupName)
+	}
+	for _, res := range out.Reservations {
+		if len(res.Instances) > 0 {
+			return nil
+		}
+	}
+
+	klog.V(2).Infof("Deleting unused placement group %q", groupName)
+	if _, err := s.scope.EC2.DeletePlacementGroup(&ec2.DeletePlacementGroupInput{GroupName: aws.String(groupName)}); err != nil {
+		return errors.Wrapf(err, "failed to delete placement group %q", groupName)
+	}
+
+	return nil
+}
+
+// AdoptInstance looks for a running instance matching machine's cluster-owned + Name tags and,
+// if found, takes ownership of it by applying the full managed tag set, so that clusters created
+// out-of-band (e.g. kops' ImportAWSCluster) or instances surviving a manual console edit are
+// reconciled under this Machine rather than a duplicate being created.
+func (s *Service) AdoptInstance(machine *actuators.MachineScope) (*v1alpha1.Instance, error) {
+	instance, err := s.InstanceByTags(machine)
+	if err != nil || instance == nil {
+		return instance, err
+	}
+
+	desiredTags := tags.Build(tags.BuildParams{
+		ClusterName: s.scope.Scope.Name(),
+		Lifecycle:   tags.ResourceLifecycleOwned,
+		Name:        aws.String(machine.Name()),
+		Role:        aws.String(machine.Role()),
+	})
+
+	if err := s.UpdateResourceTags(aws.String(instance.ID), desiredTags, nil); err != nil {
+		return nil, errors.Wrapf(err, "failed to adopt instance %q for machine %q", instance.ID, machine.Name())
+	}
+
+	record.Eventf(machine.Machine, "AdoptedInstance", "Adopted existing instance %q for machine %q", instance.ID, machine.Name())
+	return instance, nil
+}
+
+// ReconcileInstanceDrift diffs the tags and security groups of machine's running instance
+// against the desired state computed from tags.BuildParams and the Machine spec, converging
+// any drift (e.g. from a manual console edit) by calling UpdateResourceTags /
+// UpdateInstanceSecurityGroups. CreateOrGetMachine calls this every time it finds machine's
+// instance already running, so drift is reconciled on the machine controller's normal periodic
+// resync rather than needing a dedicated timer.
+func (s *Service) ReconcileInstanceDrift(machine *actuators.MachineScope) error {
+	if machine.MachineStatus.InstanceID == nil {
+		return nil
+	}
+
+	instance, err := s.InstanceIfExists(*machine.MachineStatus.InstanceID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to look up machine %q for drift reconciliation", machine.Name())
+	}
+	if instance == nil {
+		return nil
+	}
+
+	desiredTags := tags.Build(tags.BuildParams{
+		ClusterName: s.scope.Scope.Name(),
+		Lifecycle:   tags.ResourceLifecycleOwned,
+		Name:        aws.String(machine.Name()),
+		Role:        aws.String(machine.Role()),
+	})
+
+	if drifted := desiredTags.Difference(tags.Map(instance.Tags)); len(drifted) > 0 {
+		klog.V(2).Infof("Reconciling tag drift on instance %q for machine %q: %v", instance.ID, machine.Name(), drifted)
+		if err := s.UpdateResourceTags(aws.String(instance.ID), drifted, nil); err != nil {
+			return errors.Wrapf(err, "failed to reconcile tag drift on instance %q", instance.ID)
+		}
+	}
+
+	desiredSecurityGroupIDs := s.securityGroupIDsForRole(machine.Role())
+	if len(desiredSecurityGroupIDs) > 0 && !stringSlicesEqualUnordered(desiredSecurityGroupIDs, instance.SecurityGroupIDs) {
+		klog.V(2).Infof("Reconciling security group drift on instance %q for machine %q: want %v, have %v", instance.ID, machine.Name(), desiredSecurityGroupIDs, instance.SecurityGroupIDs)
+		if err := s.UpdateInstanceSecurityGroups(instance.ID, desiredSecurityGroupIDs); err != nil {
+			return errors.Wrapf(err, "failed to reconcile security group drift on instance %q", instance.ID)
+		}
+	}
+
+	return nil
+}
+
+// securityGroupIDsForRole returns the security group IDs that should be attached to an
+// instance of the given role, mirroring the logic in createInstance.
+func (s *Service) securityGroupIDsForRole(role string) []string {
+	var ids []string
+	switch role {
+	case "controlplane":
+		if sg := s.scope.Scope.SecurityGroups()[v1alpha1.SecurityGroupControlPlane]; sg != nil {
+			ids = append(ids, sg.ID)
+		}
+	case "node":
+		if s.scope.Scope.Cluster != nil {
+			if sg := s.scope.Scope.SecurityGroups()[v1alpha1.SecurityGroupNode]; sg != nil {
+				ids = append(ids, sg.ID)
+			}
+		}
+		for _, id := range s.scope.SecurityGroups() {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func stringSlicesEqualUnordered(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int, len(a))
+	for _, v := range a {
+		counts[v]++
+	}
+	for _, v := range b {
+		counts[v]--
+	}
+	for _, c := range counts {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
 }
 
 // InstanceIfExists returns the existing instance or nothing if it doesn't exist.
@@ -111,11 +318,33 @@ func (s *Service) createInstance(machine *actuators.MachineScope, bootstrapToken
 		Name:        aws.String(machine.Name()),
 		Role:        aws.String(machine.Role()),
 	})
+
 	//TODO (vikasc): do in proper way using BuildParams as above
 	//TODO (vikasc): use clusterName only and not cluster-id. Remove cluster-id once changes are done in MAO/installer to use clusterName and not ID
 	input.Tags["clusterid"] = machine.ClusterID()
 	input.Tags["kubernetes.io/cluster/"+machine.ClusterID()] = "owned"
 
+	input.SpotMarketOptions = machine.MachineConfig.SpotMarketOptions
+	input.RootVolume = machine.MachineConfig.RootVolume
+	input.DataVolumes = machine.MachineConfig.DataVolumes
+	input.MetadataOptions = machine.MachineConfig.MetadataOptions
+	input.Placement = &machine.MachineConfig.Placement
+	input.CPUOptions = machine.MachineConfig.CPUOptions
+
+	if machine.MachineConfig.Placement.GroupName != "" {
+		if err := s.ensurePlacementGroup(machine.MachineConfig.Placement.GroupName, machine.MachineConfig.Placement.GroupStrategy); err != nil {
+			return nil, err
+		}
+	}
+
+	input.ClientToken = clientTokenFor(machine)
+	if prior, err := s.instanceByClientToken(input.ClientToken); err != nil {
+		return nil, errors.Wrapf(err, "failed to check for a prior instance of machine %q", machine.Name())
+	} else if prior != nil {
+		klog.V(2).Infof("Found existing instance %q for machine %q from a prior RunInstances call with the same client token", prior.ID, machine.Name())
+		return prior, nil
+	}
+
 	var err error
 	// Pick image from the machine configuration, or use a default one.
 	if machine.MachineConfig.AMI.ID != nil {
@@ -287,6 +516,110 @@ func (s *Service) createInstance(machine *actuators.MachineScope, bootstrapToken
 	return out, nil
 }
 
+// clientTokenFor computes a deterministic RunInstances ClientToken for machine so that retrying
+// after a transient error (one where AWS may have already accepted the request) does not launch
+// a duplicate instance. The Machine's generation is folded in so that an intentional recreate
+// (e.g. after the Machine spec changes and the instance is deleted) gets a fresh token.
+func clientTokenFor(machine *actuators.MachineScope) string {
+	h := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%s/%d", machine.Namespace(), machine.Name(), machine.Machine.UID, machine.Machine.Generation)))
+	return hex.EncodeToString(h[:])[:36]
+}
+
+// instanceByClientToken looks for a non-terminated instance previously launched with the given
+// RunInstances ClientToken, so a retried createInstance call can discover a prior success
+// instead of launching a second instance.
+func (s *Service) instanceByClientToken(clientToken string) (*v1alpha1.Instance, error) {
+	input := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{Name: aws.String("client-token"), Values: aws.StringSlice([]string{clientToken})},
+			filter.EC2.InstanceStates(ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning),
+		},
+	}
+
+	out, err := s.scope.EC2.DescribeInstances(input)
+	switch {
+	case awserrors.IsNotFound(err):
+		return nil, nil
+	case err != nil:
+		return nil, errors.Wrap(err, "failed to describe instances by client token")
+	}
+
+	if len(out.Reservations) > 0 && len(out.Reservations[0].Instances) > 0 {
+		return converters.SDKToInstance(out.Reservations[0].Instances[0]), nil
+	}
+
+	return nil, nil
+}
+
+// buildBlockDeviceMappings translates the RootVolume and DataVolumes configured on i into
+// RunInstancesInput.BlockDeviceMappings, looking up the AMI's root device name when the root
+// volume was only configured with a size/type (no explicit device name).
+func (s *Service) buildBlockDeviceMappings(i *v1alpha1.Instance) ([]*ec2.BlockDeviceMapping, error) {
+	var mappings []*ec2.BlockDeviceMapping
+
+	if i.RootVolume != nil {
+		deviceName := i.RootVolume.DeviceName
+		if deviceName == "" {
+			rootDeviceName, err := s.rootDeviceName(i.ImageID)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to look up root device name for AMI %q", i.ImageID)
+			}
+			deviceName = rootDeviceName
+		}
+		mappings = append(mappings, volumeBlockDeviceMapping(deviceName, i.RootVolume))
+	}
+
+	for idx := range i.DataVolumes {
+		volume := i.DataVolumes[idx]
+		mappings = append(mappings, volumeBlockDeviceMapping(volume.DeviceName, &volume))
+	}
+
+	return mappings, nil
+}
+
+// rootDeviceName returns the root device name (e.g. "/dev/sda1") of the given AMI.
+func (s *Service) rootDeviceName(imageID string) (string, error) {
+	out, err := s.scope.EC2.DescribeImages(&ec2.DescribeImagesInput{
+		ImageIds: aws.StringSlice([]string{imageID}),
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(out.Images) == 0 || out.Images[0].RootDeviceName == nil {
+		return "", errors.Errorf("no root device name found for AMI %q", imageID)
+	}
+	return *out.Images[0].RootDeviceName, nil
+}
+
+func volumeBlockDeviceMapping(deviceName string, volume *v1alpha1.Volume) *ec2.BlockDeviceMapping {
+	ebs := &ec2.EbsBlockDevice{
+		VolumeSize: aws.Int64(volume.Size),
+	}
+	if volume.Type != "" {
+		ebs.VolumeType = aws.String(volume.Type)
+	}
+	if volume.IOPS > 0 {
+		ebs.Iops = aws.Int64(volume.IOPS)
+	}
+	if volume.Throughput > 0 {
+		ebs.Throughput = aws.Int64(volume.Throughput)
+	}
+	if volume.Encrypted != nil {
+		ebs.Encrypted = volume.Encrypted
+	}
+	if volume.KMSKeyID != "" && aws.BoolValue(volume.Encrypted) {
+		ebs.KmsKeyId = aws.String(volume.KMSKeyID)
+	}
+	if volume.DeleteOnTermination != nil {
+		ebs.DeleteOnTermination = volume.DeleteOnTermination
+	}
+
+	return &ec2.BlockDeviceMapping{
+		DeviceName: aws.String(deviceName),
+		Ebs:        ebs,
+	}
+}
+
 func buildEC2Filters(inputFilters []v1alpha1.Filter) []*ec2.Filter {
 	filters := make([]*ec2.Filter, len(inputFilters))
 	for i, f := range inputFilters {
@@ -302,11 +635,44 @@ func buildEC2Filters(inputFilters []v1alpha1.Filter) []*ec2.Filter {
 	return filters
 }
 
-// TerminateInstance terminates an EC2 instance.
+// instanceTeardownInfo looks up the Spot Instance request and placement group, if any, backing
+// instanceID so that TerminateInstance can clean both up. A lookup failure (including the
+// instance already being gone) is treated as "nothing more to clean up" rather than an error,
+// since the instance is about to be terminated either way.
+func (s *Service) instanceTeardownInfo(instanceID string) (spotRequestID string, placementGroupName string) {
+	out, err := s.scope.EC2.DescribeInstances(&ec2.DescribeInstancesInput{
+		InstanceIds: aws.StringSlice([]string{instanceID}),
+	})
+	if err != nil || len(out.Reservations) == 0 || len(out.Reservations[0].Instances) == 0 {
+		return "", ""
+	}
+
+	instance := out.Reservations[0].Instances[0]
+	if instance.SpotInstanceRequestId != nil {
+		spotRequestID = *instance.SpotInstanceRequestId
+	}
+	if instance.Placement != nil && instance.Placement.GroupName != nil {
+		placementGroupName = *instance.Placement.GroupName
+	}
+	return spotRequestID, placementGroupName
+}
+
+// TerminateInstance terminates an EC2 instance. If the instance was backed by a Spot Instance
+// request, that request is cancelled first so that a persistent request does not re-launch a
+// replacement instance after this one is terminated. If the instance was the last one in its
+// placement group, the group is reclaimed too.
 // Returns nil on success, error in all other cases.
 func (s *Service) TerminateInstance(instanceID string) error {
 	klog.V(2).Infof("Attempting to terminate instance with id %q", instanceID)
 
+	spotRequestID, placementGroupName := s.instanceTeardownInfo(instanceID)
+
+	if spotRequestID != "" {
+		if err := s.CancelSpotInstanceRequest(spotRequestID); err != nil {
+			return err
+		}
+	}
+
 	input := &ec2.TerminateInstancesInput{
 		InstanceIds: aws.StringSlice([]string{instanceID}),
 	}
@@ -321,6 +687,13 @@ func (s *Service) TerminateInstance(instanceID string) error {
 	} else {
 		record.Eventf(s.scope.Machine, "DeletedInstance", "Terminated instance %q", instanceID)
 	}
+
+	if placementGroupName != "" {
+		if err := s.DeletePlacementGroupIfUnused(placementGroupName); err != nil {
+			return errors.Wrapf(err, "failed to delete placement group %q after terminating instance %q", placementGroupName, instanceID)
+		}
+	}
+
 	return nil
 }
 
@@ -356,19 +729,87 @@ func (s *Service) CreateOrGetMachine(machine *actuators.MachineScope, bootstrapT
 		if err != nil && !awserrors.IsNotFound(err) {
 			return nil, errors.Wrapf(err, "failed to look up machine %q by id %q", machine.Name(), *machine.MachineStatus.InstanceID)
 		} else if err == nil && instance != nil {
+			if err := s.ReconcileInstanceDrift(machine); err != nil {
+				return nil, errors.Wrapf(err, "failed to reconcile drift for machine %q", machine.Name())
+			}
 			return instance, nil
 		}
+
+		if machine.MachineConfig.SpotMarketOptions != nil {
+			s.checkSpotInterruption(machine, *machine.MachineStatus.InstanceID)
+		}
 	}
 
 	klog.V(2).Infof("Looking up machine %q by tags", machine.Name())
-	instance, err := s.InstanceByTags(machine)
+	instance, err := s.AdoptInstance(machine)
 	if err != nil && !awserrors.IsNotFound(err) {
-		return nil, errors.Wrapf(err, "failed to query machine %q instance by tags", machine.Name())
+		return nil, errors.Wrapf(err, "failed to adopt machine %q by tags", machine.Name())
 	} else if err == nil && instance != nil {
+		reconcileSpotRequestID(machine, instance)
 		return instance, nil
 	}
 
-	return s.createInstance(machine, bootstrapToken)
+	// A spot request may already be in flight from a previous reconcile; avoid requesting a
+	// second instance until we know whether it was fulfilled, cancelled, or still pending.
+	if machine.MachineStatus.SpotRequestID != nil {
+		fulfilled, instanceID, err := s.spotRequestFulfilled(*machine.MachineStatus.SpotRequestID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to describe spot instance request %q for machine %q", *machine.MachineStatus.SpotRequestID, machine.Name())
+		}
+		if !fulfilled {
+			klog.V(2).Infof("Spot instance request %q for machine %q is still pending", *machine.MachineStatus.SpotRequestID, machine.Name())
+			return nil, nil
+		}
+		instance, err := s.InstanceIfExists(instanceID)
+		if err != nil {
+			return nil, err
+		}
+		reconcileSpotRequestID(machine, instance)
+		return instance, nil
+	}
+
+	instance, err = s.createInstance(machine, bootstrapToken)
+	if err != nil {
+		return nil, err
+	}
+	reconcileSpotRequestID(machine, instance)
+	return instance, nil
+}
+
+// reconcileSpotRequestID keeps MachineStatus.SpotRequestID in sync with instance so that a
+// later reconcile of the same Machine knows whether a Spot Instance request is still pending
+// (see the check above) instead of issuing a second RunInstances call for it.
+func reconcileSpotRequestID(machine *actuators.MachineScope, instance *v1alpha1.Instance) {
+	if instance == nil {
+		return
+	}
+	if instance.SpotRequestID == "" {
+		machine.MachineStatus.SpotRequestID = nil
+		return
+	}
+	machine.MachineStatus.SpotRequestID = aws.String(instance.SpotRequestID)
+}
+
+// spotRequestFulfilled returns whether a Spot Instance request has reached the "fulfilled"
+// state and, if so, the ID of the instance it launched.
+func (s *Service) spotRequestFulfilled(spotRequestID string) (bool, string, error) {
+	out, err := s.scope.EC2.DescribeSpotInstanceRequests(&ec2.DescribeSpotInstanceRequestsInput{
+		SpotInstanceRequestIds: aws.StringSlice([]string{spotRequestID}),
+	})
+	if err != nil {
+		return false, "", err
+	}
+
+	if len(out.SpotInstanceRequests) == 0 {
+		return false, "", errors.Errorf("spot instance request %q not found", spotRequestID)
+	}
+
+	req := out.SpotInstanceRequests[0]
+	if req.State == nil || *req.State != ec2.SpotInstanceStateActive || req.InstanceId == nil {
+		return false, "", nil
+	}
+
+	return true, *req.InstanceId, nil
 }
 
 func (s *Service) runInstance(role string, i *v1alpha1.Instance) (*v1alpha1.Instance, error) {
@@ -380,6 +821,7 @@ func (s *Service) runInstance(role string, i *v1alpha1.Instance) (*v1alpha1.Inst
 		MaxCount:     aws.Int64(1),
 		MinCount:     aws.Int64(1),
 		UserData:     i.UserData,
+		ClientToken:  aws.String(i.ClientToken),
 	}
 	if len(i.NetworkInterfaces) == 0 {
 		input.SubnetId = aws.String(i.SubnetID)
@@ -403,20 +845,83 @@ func (s *Service) runInstance(role string, i *v1alpha1.Instance) (*v1alpha1.Inst
 		}
 	}
 
-	if len(i.Tags) > 0 {
-		spec := &ec2.TagSpecification{ResourceType: aws.String(ec2.ResourceTypeInstance)}
-		for key, value := range i.Tags {
-			spec.Tags = append(spec.Tags, &ec2.Tag{
-				Key:   aws.String(key),
-				Value: aws.String(value),
-			})
+	if i.SpotMarketOptions != nil {
+		spotOptions := &ec2.SpotMarketOptions{
+			SpotInstanceType:             aws.String(ec2.SpotInstanceTypeOneTime),
+			InstanceInterruptionBehavior: aws.String(ec2.InstanceInterruptionBehaviorTerminate),
+			MaxPrice:                     i.SpotMarketOptions.MaxPrice,
+			BlockDurationMinutes:         i.SpotMarketOptions.BlockDurationMinutes,
+		}
+		if i.SpotMarketOptions.SpotInstanceType != "" {
+			spotOptions.SpotInstanceType = aws.String(i.SpotMarketOptions.SpotInstanceType)
+		}
+		if i.SpotMarketOptions.InstanceInterruptionBehavior != "" {
+			spotOptions.InstanceInterruptionBehavior = aws.String(i.SpotMarketOptions.InstanceInterruptionBehavior)
+		}
+		input.InstanceMarketOptions = &ec2.InstanceMarketOptionsRequest{
+			MarketType:  aws.String(ec2.MarketTypeSpot),
+			SpotOptions: spotOptions,
+		}
+	}
+
+	if p := i.Placement; p != nil && (p.GroupName != "" || p.Tenancy != "" || p.HostID != "" || p.PartitionNumber != 0) {
+		placement := &ec2.Placement{}
+		if p.GroupName != "" {
+			placement.GroupName = aws.String(p.GroupName)
+		}
+		if p.Tenancy != "" {
+			placement.Tenancy = aws.String(p.Tenancy)
+		}
+		if p.HostID != "" && p.Tenancy == ec2.TenancyHost {
+			placement.HostId = aws.String(p.HostID)
+		}
+		if p.PartitionNumber != 0 && p.GroupStrategy == ec2.PlacementStrategyPartition {
+			placement.PartitionNumber = aws.Int64(p.PartitionNumber)
+		}
+		input.Placement = placement
+	}
+
+	if i.CPUOptions != nil {
+		input.CpuOptions = &ec2.CpuOptionsRequest{
+			CoreCount:      aws.Int64(i.CPUOptions.CoreCount),
+			ThreadsPerCore: aws.Int64(i.CPUOptions.ThreadsPerCore),
+		}
+	}
+
+	if i.MetadataOptions != nil {
+		metadataOptions := &ec2.InstanceMetadataOptionsRequest{}
+		if i.MetadataOptions.HTTPTokens != "" {
+			metadataOptions.HttpTokens = aws.String(i.MetadataOptions.HTTPTokens)
+		}
+		if i.MetadataOptions.HTTPPutResponseHopLimit > 0 {
+			metadataOptions.HttpPutResponseHopLimit = aws.Int64(i.MetadataOptions.HTTPPutResponseHopLimit)
+		}
+		if i.MetadataOptions.HTTPEndpoint != "" {
+			metadataOptions.HttpEndpoint = aws.String(i.MetadataOptions.HTTPEndpoint)
 		}
+		input.MetadataOptions = metadataOptions
+	}
+
+	if blockDeviceMappings, err := s.buildBlockDeviceMappings(i); err != nil {
+		return nil, err
+	} else if len(blockDeviceMappings) > 0 {
+		input.BlockDeviceMappings = blockDeviceMappings
+	}
 
-		input.TagSpecifications = append(input.TagSpecifications, spec)
+	var volumeTags []*ec2.Tag
+	for key, value := range i.Tags {
+		volumeTags = append(volumeTags, &ec2.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+
+	if len(i.Tags) > 0 {
+		input.TagSpecifications = append(input.TagSpecifications, &ec2.TagSpecification{
+			ResourceType: aws.String(ec2.ResourceTypeInstance),
+			Tags:         volumeTags,
+		})
 	}
 	input.TagSpecifications = append(input.TagSpecifications, &ec2.TagSpecification{
 		ResourceType: aws.String("volume"),
-		Tags:         []*ec2.Tag{{Key: aws.String("clusterid"), Value: aws.String(s.scope.ClusterID())}},
+		Tags:         volumeTags,
 	})
 
 	out, err := s.scope.EC2.RunInstances(input)
@@ -429,7 +934,49 @@ func (s *Service) runInstance(role string, i *v1alpha1.Instance) (*v1alpha1.Inst
 	}
 
 	s.scope.EC2.WaitUntilInstanceRunning(&ec2.DescribeInstancesInput{InstanceIds: []*string{out.Instances[0].InstanceId}})
-	return converters.SDKToInstance(out.Instances[0]), nil
+
+	instance := converters.SDKToInstance(out.Instances[0])
+	if out.Instances[0].SpotInstanceRequestId != nil {
+		instance.SpotRequestID = *out.Instances[0].SpotInstanceRequestId
+	}
+	return instance, nil
+}
+
+// CancelSpotInstanceRequest cancels a pending or persistent Spot Instance request so that
+// AWS does not launch a replacement instance after the current one is terminated.
+func (s *Service) CancelSpotInstanceRequest(spotRequestID string) error {
+	klog.V(2).Infof("Attempting to cancel spot instance request %q", spotRequestID)
+
+	input := &ec2.CancelSpotInstanceRequestsInput{
+		SpotInstanceRequestIds: aws.StringSlice([]string{spotRequestID}),
+	}
+
+	if _, err := s.scope.EC2.CancelSpotInstanceRequests(input); err != nil {
+		return errors.Wrapf(err, "failed to cancel spot instance request %q", spotRequestID)
+	}
+
+	return nil
+}
+
+// checkSpotInterruption looks up an instance that is no longer pending/running and, if its
+// state transition reason indicates the Spot market reclaimed it, records an interruption event.
+func (s *Service) checkSpotInterruption(machine *actuators.MachineScope, instanceID string) {
+	out, err := s.scope.EC2.DescribeInstances(&ec2.DescribeInstancesInput{
+		InstanceIds: []*string{aws.String(instanceID)},
+	})
+	if err != nil || len(out.Reservations) == 0 || len(out.Reservations[0].Instances) == 0 {
+		return
+	}
+
+	instance := out.Reservations[0].Instances[0]
+	if instance.StateTransitionReason == nil {
+		return
+	}
+
+	reason := *instance.StateTransitionReason
+	if strings.Contains(reason, "Server.SpotInstanceTermination") || strings.Contains(reason, "Server.SpotInstanceShutdown") {
+		record.Eventf(machine.Machine, "SpotInstanceInterrupted", "Spot instance %q was interrupted: %s", instanceID, reason)
+	}
 }
 
 // UpdateInstanceSecurityGroups modifies the security groups of the given
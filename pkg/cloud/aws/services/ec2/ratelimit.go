@@ -0,0 +1,306 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+	"k8s.io/klog"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/aws/services/awserrors"
+)
+
+// cacheTTL is how long a Describe* response is reused across reconciles of the same request.
+const cacheTTL = 10 * time.Second
+
+// throttleBackoff* bound the retry done by withThrottleBackoff when AWS itself reports
+// RequestLimitExceeded/Throttling, on top of the client-side limiter's own pacing.
+const (
+	throttleBackoffSteps       = 5
+	throttleBackoffInitialWait = 500 * time.Millisecond
+	throttleBackoffFactor      = 2.0
+)
+
+var (
+	ec2RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "capa_ec2_request_duration_seconds",
+		Help: "Duration in seconds of AWS EC2 API requests made by the provider, by operation.",
+	}, []string{"operation"})
+
+	ec2RequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "capa_ec2_request_errors_total",
+		Help: "Total number of AWS EC2 API request errors, by operation.",
+	}, []string{"operation"})
+
+	ec2RequestThrottles = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "capa_ec2_request_throttles_total",
+		Help: "Total number of AWS EC2 API requests throttled by AWS, by operation.",
+	}, []string{"operation"})
+)
+
+func init() {
+	prometheus.MustRegister(ec2RequestDuration, ec2RequestErrors, ec2RequestThrottles)
+}
+
+// rateLimitedEC2 decorates an ec2iface.EC2API with pagination of list calls, a short-TTL
+// in-memory response cache, a client-side token-bucket limiter, and Prometheus metrics.
+// It exists so that reconciling many Machines in a MachineSet does not trip EC2's per-account
+// request throttling.
+type rateLimitedEC2 struct {
+	ec2iface.EC2API
+
+	limiter *rate.Limiter
+	cache   *responseCache
+}
+
+// NewRateLimitedClient wraps client so that list operations are paginated, rate limited to
+// qps/burst, cached for a short TTL, and instrumented. qps <= 0 disables the limiter.
+// NewServiceWithRateLimit wraps its scope's EC2 client with this, so every call issued through
+// that Service shares throttling headroom and cached results.
+func NewRateLimitedClient(client ec2iface.EC2API, qps float64, burst int) ec2iface.EC2API {
+	var limiter *rate.Limiter
+	if qps > 0 {
+		limiter = rate.NewLimiter(rate.Limit(qps), burst)
+	}
+
+	return &rateLimitedEC2{
+		EC2API:  client,
+		limiter: limiter,
+		cache:   newResponseCache(cacheTTL),
+	}
+}
+
+// wait blocks until the client-side limiter permits another request. Back-off on throttling
+// reported by AWS itself is done separately, by withThrottleBackoff.
+func (r *rateLimitedEC2) wait(operation string) error {
+	if r.limiter == nil {
+		return nil
+	}
+	return r.limiter.Wait(context.Background())
+}
+
+// withThrottleBackoff calls fn, retrying with exponential backoff whenever it fails with an AWS
+// throttling/RequestLimitExceeded error, so a reconcile that races past the client-side limiter
+// anyway (e.g. another controller sharing the same account) recovers instead of immediately
+// surfacing the throttle to the caller as a reconcile error.
+func withThrottleBackoff(operation string, fn func() error) error {
+	backoff := throttleBackoffInitialWait
+	var err error
+	for attempt := 0; attempt < throttleBackoffSteps; attempt++ {
+		if err = fn(); err == nil || !awserrors.IsThrottle(err) {
+			return err
+		}
+		if attempt == throttleBackoffSteps-1 {
+			break
+		}
+		klog.V(2).Infof("%s throttled by AWS, backing off %v before retrying", operation, backoff)
+		time.Sleep(backoff)
+		backoff = time.Duration(float64(backoff) * throttleBackoffFactor)
+	}
+	return err
+}
+
+func (r *rateLimitedEC2) observe(operation string, start time.Time, err error) {
+	ec2RequestDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if err == nil {
+		return
+	}
+	ec2RequestErrors.WithLabelValues(operation).Inc()
+	if awserrors.IsThrottle(err) {
+		ec2RequestThrottles.WithLabelValues(operation).Inc()
+	}
+}
+
+// DescribeInstances paginates over every page of results and caches the aggregated response.
+func (r *rateLimitedEC2) DescribeInstances(input *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+	const operation = "DescribeInstances"
+
+	if cached, ok := r.cache.get(operation, input); ok {
+		return cached.(*ec2.DescribeInstancesOutput), nil
+	}
+
+	if err := r.wait(operation); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	out := &ec2.DescribeInstancesOutput{}
+	err := withThrottleBackoff(operation, func() error {
+		out.Reservations = nil
+		return r.EC2API.DescribeInstancesPages(input, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+			out.Reservations = append(out.Reservations, page.Reservations...)
+			return true
+		})
+	})
+	r.observe(operation, start, err)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache.put(operation, input, out)
+	return out, nil
+}
+
+// DescribeSubnets paginates over every page of results and caches the aggregated response.
+func (r *rateLimitedEC2) DescribeSubnets(input *ec2.DescribeSubnetsInput) (*ec2.DescribeSubnetsOutput, error) {
+	const operation = "DescribeSubnets"
+
+	if cached, ok := r.cache.get(operation, input); ok {
+		return cached.(*ec2.DescribeSubnetsOutput), nil
+	}
+
+	if err := r.wait(operation); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	out := &ec2.DescribeSubnetsOutput{}
+	err := withThrottleBackoff(operation, func() error {
+		out.Subnets = nil
+		return r.EC2API.DescribeSubnetsPages(input, func(page *ec2.DescribeSubnetsOutput, lastPage bool) bool {
+			out.Subnets = append(out.Subnets, page.Subnets...)
+			return true
+		})
+	})
+	r.observe(operation, start, err)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache.put(operation, input, out)
+	return out, nil
+}
+
+// DescribeSecurityGroups paginates over every page of results and caches the aggregated response.
+func (r *rateLimitedEC2) DescribeSecurityGroups(input *ec2.DescribeSecurityGroupsInput) (*ec2.DescribeSecurityGroupsOutput, error) {
+	const operation = "DescribeSecurityGroups"
+
+	if cached, ok := r.cache.get(operation, input); ok {
+		return cached.(*ec2.DescribeSecurityGroupsOutput), nil
+	}
+
+	if err := r.wait(operation); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	out := &ec2.DescribeSecurityGroupsOutput{}
+	err := withThrottleBackoff(operation, func() error {
+		out.SecurityGroups = nil
+		return r.EC2API.DescribeSecurityGroupsPages(input, func(page *ec2.DescribeSecurityGroupsOutput, lastPage bool) bool {
+			out.SecurityGroups = append(out.SecurityGroups, page.SecurityGroups...)
+			return true
+		})
+	})
+	r.observe(operation, start, err)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache.put(operation, input, out)
+	return out, nil
+}
+
+// DescribeAvailabilityZones caches the response; this call has no continuation token but is
+// issued repeatedly for the same region on every reconcile of a Machine with an AZ override.
+func (r *rateLimitedEC2) DescribeAvailabilityZones(input *ec2.DescribeAvailabilityZonesInput) (*ec2.DescribeAvailabilityZonesOutput, error) {
+	const operation = "DescribeAvailabilityZones"
+
+	if cached, ok := r.cache.get(operation, input); ok {
+		return cached.(*ec2.DescribeAvailabilityZonesOutput), nil
+	}
+
+	if err := r.wait(operation); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	var out *ec2.DescribeAvailabilityZonesOutput
+	err := withThrottleBackoff(operation, func() error {
+		var apiErr error
+		out, apiErr = r.EC2API.DescribeAvailabilityZones(input)
+		return apiErr
+	})
+	r.observe(operation, start, err)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache.put(operation, input, out)
+	return out, nil
+}
+
+// responseCache is a short-TTL, in-memory cache of Describe* responses keyed on the
+// marshaled request so that repeated reconciles of the same MachineSet share results
+// instead of each issuing its own EC2 API call.
+type responseCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{ttl: ttl, entries: make(map[string]cacheEntry)}
+}
+
+func (c *responseCache) get(operation string, input interface{}) (interface{}, bool) {
+	key, err := cacheKey(operation, input)
+	if err != nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *responseCache) put(operation string, input interface{}, value interface{}) {
+	key, err := cacheKey(operation, input)
+	if err != nil {
+		klog.V(4).Infof("not caching %s response: %v", operation, err)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = cacheEntry{value: value, expires: time.Now().Add(c.ttl)}
+}
+
+func cacheKey(operation string, input interface{}) (string, error) {
+	marshaled, err := json.Marshal(input)
+	if err != nil {
+		return "", err
+	}
+	return operation + ":" + string(marshaled), nil
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/aws/actuators"
+)
+
+// defaultEC2QPS and defaultEC2Burst bound how fast Service issues EC2 Describe calls once
+// wrapped by NewRateLimitedClient. They are conservative enough to stay well under AWS's
+// default per-account EC2 API throttle even while reconciling a large MachineSet.
+const (
+	defaultEC2QPS   = 20.0
+	defaultEC2Burst = 40
+)
+
+// Service wraps an AWS EC2 session with the logic needed to reconcile a cluster-api Machine.
+type Service struct {
+	scope *actuators.Scope
+}
+
+// NewService returns a Service using scope, rate limited to defaultEC2QPS/defaultEC2Burst. Use
+// NewServiceWithRateLimit to override those defaults per controller.
+func NewService(scope *actuators.Scope) *Service {
+	return NewServiceWithRateLimit(scope, defaultEC2QPS, defaultEC2Burst)
+}
+
+// NewServiceWithRateLimit returns a Service using scope. scope.EC2 is wrapped with
+// NewRateLimitedClient(qps, burst) so every reconcile issued through the returned Service shares
+// pagination, caching, throttle back-off, and request metrics instead of each call site
+// reimplementing its own. qps/burst are exposed here, rather than hardcoded, so a controller
+// reconciling many Machines concurrently (and so needing more headroom than the default) can
+// raise them without forking Service.
+func NewServiceWithRateLimit(scope *actuators.Scope, qps float64, burst int) *Service {
+	scope.EC2 = NewRateLimitedClient(scope.EC2, qps, burst)
+	return &Service{scope: scope}
+}